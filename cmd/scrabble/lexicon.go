@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/tmazeika/scrabble-go/internal/dict"
+)
+
+// lexiconFlags selects a base lexicon and the house-rule overlay on top of
+// it.
+type lexiconFlags struct {
+	dict   *string
+	add    *string
+	remove *string
+}
+
+func addLexiconFlags(fs *flag.FlagSet) *lexiconFlags {
+	return &lexiconFlags{
+		dict:   fs.String("dict", dict.Dict, "lexicon file"),
+		add:    fs.String("add", "", "file of words to add to the lexicon"),
+		remove: fs.String("remove", "", "file of words to remove"),
+	}
+}
+
+func (f *lexiconFlags) load() (*dict.Node, error) {
+	root, err := dict.Load(*f.dict)
+	if err != nil {
+		return nil, err
+	}
+	return dict.LoadOverlay(root, *f.add, *f.remove)
+}
+
+const diffUsage = `usage: scrabble diff [flags] [other]
+
+Reports the words added (+) and removed (-) going from the lexicon given by
+-dict to either the other lexicon file or, without one, the lexicon with the
+-add and -remove overlay applied.
+
+flags:`
+
+func diff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	lex := addLexiconFlags(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), diffUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		fs.Usage()
+		return errors.New("diff: expected at most one other lexicon")
+	}
+	base, err := dict.Load(*lex.dict)
+	if err != nil {
+		return err
+	}
+	var other *dict.Node
+	if fs.NArg() == 1 {
+		other, err = dict.Load(fs.Arg(0))
+	} else {
+		other, err = dict.LoadOverlay(base, *lex.add, *lex.remove)
+	}
+	if err != nil {
+		return err
+	}
+	added, removed := dict.Diff(base, other)
+	for _, w := range added {
+		fmt.Println("+" + w)
+	}
+	for _, w := range removed {
+		fmt.Println("-" + w)
+	}
+	fmt.Printf("%d added, %d removed\n", len(added), len(removed))
+	return nil
+}
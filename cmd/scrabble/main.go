@@ -1,8 +1,9 @@
 package main
 
 import (
+	"flag"
 	"fmt"
-	"github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/leaves"
 	"github.com/tmazeika/scrabble-go/internal/scrabble"
 	"math/rand"
 	"os"
@@ -16,17 +17,33 @@ var gameLeads [][]int
 
 func main() {
 	rand.Seed(time.Now().UnixNano())
-	if err := play(); err != nil {
+	var err error
+	switch {
+	case len(os.Args) > 1 && os.Args[1] == "words":
+		err = words(os.Args[2:])
+	case len(os.Args) > 1 && os.Args[1] == "diff":
+		err = diff(os.Args[2:])
+	case len(os.Args) > 1 && os.Args[1] == "match":
+		err = match(os.Args[2:])
+	case len(os.Args) > 1 && os.Args[1] == "train":
+		err = trainLeaves(os.Args[2:])
+	default:
+		err = play(os.Args[1:])
+	}
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
+}
+
+func printStats() {
 	for name, wins := range winners {
 		fmt.Printf("%d wins for %s\n", wins, name)
 	}
 	for _, game := range gameLeads {
 		for i, lead := range game {
 			fmt.Printf("%d", lead)
-			if i < len(game) - 1 {
+			if i < len(game)-1 {
 				fmt.Print(", ")
 			}
 		}
@@ -34,17 +51,50 @@ func main() {
 	}
 }
 
-func play() error {
-	root, err := dict.Load(dict.Dict)
+func play(args []string) error {
+	fs := flag.NewFlagSet("scrabble", flag.ContinueOnError)
+	lex := addLexiconFlags(fs)
+	human := fs.String("human", "",
+		"play against the computer as this player, entering moves like 8H WORD")
+	leaveFile := fs.String("leaves", "",
+		"play the first computer player by equity, with leave values "+
+			"from this `file`")
+	budget := fs.Duration("budget", 0,
+		"time the MCTS player spends on each move, instead of a fixed "+
+			"number of iterations")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	root, err := lex.load()
 	if err != nil {
 		return err
 	}
+	var table *leaves.Table
+	if *leaveFile != "" {
+		if table, err = leaves.Load(*leaveFile); err != nil {
+			return err
+		}
+	}
 	for i := 0; i < Trials; i++ {
 		// var leads []int
-		player1 := scrabble.NewComputerPlayer("MostPoints",
+		var player1 scrabble.Player = scrabble.NewComputerPlayer("MostPoints",
 			scrabble.MostPointsStrategy)
-		player2 := scrabble.NewComputerPlayer("MCTS-AI",
-			scrabble.NewMCTSStrategy(25, 10, 1.4))
+		if *human != "" {
+			player1 = scrabble.NewHumanPlayer(*human)
+		} else if table != nil {
+			player1 = scrabble.NewComputerPlayer("Equity",
+				scrabble.NewEquityStrategy(table))
+		}
+		mcts := scrabble.NewMCTSStrategy(25, 10, 1.4)
+		if *budget > 0 {
+			mcts = scrabble.NewMCTS(scrabble.MCTSOptions{
+				Budget:  *budget,
+				PickTop: 10,
+				C:       1.4,
+				Reuse:   true,
+			}).Play
+		}
+		player2 := scrabble.NewComputerPlayer("MCTS-AI", mcts)
 		game := scrabble.NewGame(root, player1, player2)
 		for !game.Over() {
 			fmt.Println(game.String())
@@ -66,5 +116,6 @@ func play() error {
 			winners[p.Name()]++
 		}
 	}
+	printStats()
 	return nil
 }
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/tmazeika/scrabble-go/internal/leaves"
+	"github.com/tmazeika/scrabble-go/internal/scrabble"
+	"time"
+)
+
+const matchUsage = `usage: scrabble match [flags] <strategy> <strategy>
+
+Plays games between two computer strategies, taking turns going first, and
+reports how the first did.
+
+strategies:
+  random    a random move
+  points    the highest scoring move
+  equity    the move with the most points plus leave value
+  mcts      Monte Carlo tree search over the -pick highest scoring moves
+  mcts-pw   Monte Carlo tree search with progressive widening by -prior
+
+flags:`
+
+func match(args []string) error {
+	fs := flag.NewFlagSet("match", flag.ContinueOnError)
+	lex := addLexiconFlags(fs)
+	games := fs.Int("games", 10, "number of games")
+	leaveFile := fs.String("leaves", "", "leave values `file` for equity")
+	prior := fs.String("prior", "equity",
+		"order mcts-pw tries moves in: points or equity")
+	var opts scrabble.MCTSOptions
+	fs.IntVar(&opts.Iterations, "iterations", 100, "MCTS iterations per move")
+	fs.DurationVar(&opts.Budget, "budget", 0,
+		"MCTS time per move (0 for no limit)")
+	fs.IntVar(&opts.PickTop, "pick", 10, "moves tried per position by mcts")
+	fs.Float64Var(&opts.C, "c", 1.4, "MCTS exploration constant")
+	fs.IntVar(&opts.Parallelism, "parallelism", 0,
+		"MCTS rollouts at once (0 for one per CPU)")
+	fs.IntVar(&opts.Plies, "plies", 0,
+		"turns before an MCTS rollout is cut short (0 to play to the end)")
+	fs.Float64Var(&opts.SpreadWeight, "spread", 0,
+		"weight from 0 to 1 of spread, rather than winning, in MCTS rewards")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), matchUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("match: expected two strategies")
+	}
+	root, err := lex.load()
+	if err != nil {
+		return err
+	}
+	table := leaves.Heuristic()
+	if *leaveFile != "" {
+		if table, err = leaves.Load(*leaveFile); err != nil {
+			return err
+		}
+	}
+	opts.Reuse = true
+	if opts.Plies > 0 {
+		opts.Evaluator = scrabble.NewStaticEvaluator(table)
+	}
+	var strategies [2]func() scrabble.StrategyFunc
+	for i, name := range fs.Args() {
+		switch name {
+		case "random":
+			strategies[i] = func() scrabble.StrategyFunc {
+				return scrabble.RandomStrategy
+			}
+		case "points":
+			strategies[i] = func() scrabble.StrategyFunc {
+				return scrabble.MostPointsStrategy
+			}
+		case "equity":
+			strategies[i] = func() scrabble.StrategyFunc {
+				return scrabble.NewEquityStrategy(table)
+			}
+		case "mcts":
+			strategies[i] = func() scrabble.StrategyFunc {
+				return scrabble.NewMCTS(opts).Play
+			}
+		case "mcts-pw":
+			pw := opts
+			switch *prior {
+			case "points":
+				pw.Prior = scrabble.PointsPrior
+			case "equity":
+				pw.Prior = scrabble.NewEquityPrior(table)
+			default:
+				return fmt.Errorf("match: unknown prior %q", *prior)
+			}
+			strategies[i] = func() scrabble.StrategyFunc {
+				return scrabble.NewMCTS(pw).Play
+			}
+		default:
+			return fmt.Errorf("match: unknown strategy %q", name)
+		}
+	}
+	start := time.Now()
+	r := scrabble.Match(root, strategies[0], strategies[1],
+		scrabble.MatchOptions{Games: *games})
+	fmt.Printf("%s against %s: %d wins, %d losses, %d draws, "+
+		"%+.1f average spread (%v)\n", fs.Arg(0), fs.Arg(1), r.Wins, r.Losses,
+		r.Draws, float64(r.Spread)/float64(r.Games()),
+		time.Since(start).Round(time.Second))
+	return nil
+}
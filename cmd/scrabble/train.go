@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/tmazeika/scrabble-go/internal/train"
+	"os"
+	"runtime"
+)
+
+const trainUsage = `usage: scrabble train [flags] <out>
+
+Fits leave values by self-play between equity players and writes them to
+out, in the format read by -leaves. With -checkpoint, training can be
+stopped and picked up again from the last finished iteration.
+
+flags:`
+
+func trainLeaves(args []string) error {
+	fs := flag.NewFlagSet("train", flag.ContinueOnError)
+	lex := addLexiconFlags(fs)
+	var opts train.Options
+	fs.IntVar(&opts.Games, "games", 1000, "games per iteration")
+	fs.IntVar(&opts.Iterations, "iterations", 10,
+		"maximum number of iterations")
+	fs.Float64Var(&opts.Tolerance, "tol", 0.05,
+		"stop once the average change in leave values is below this")
+	fs.IntVar(&opts.MaxLeave, "max", 6, "most tiles in a leave given a value")
+	fs.Float64Var(&opts.Smoothing, "smoothing", 20,
+		"weight of a leave's previous value, in samples")
+	fs.IntVar(&opts.Workers, "workers", runtime.NumCPU(),
+		"games played at once")
+	fs.StringVar(&opts.Checkpoint, "checkpoint", "",
+		"`file` to save progress to after each iteration and resume from")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), trainUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("train: expected an output file")
+	}
+	root, err := lex.load()
+	if err != nil {
+		return err
+	}
+	opts.Progress = func(s train.Stats) {
+		fmt.Printf("iteration %d: %d samples of %d leaves, change %.3f\n",
+			s.Iteration, s.Samples, s.Seen, s.Change)
+	}
+	table, err := train.Train(root, opts)
+	if err != nil {
+		return err
+	}
+	f, err := os.Create(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	if err := table.Write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/tmazeika/scrabble-go/internal/dict"
+	"sort"
+	"strings"
+)
+
+const wordsUsage = `usage: scrabble words [flags] <query> <letters>
+
+queries:
+  anagram     words using every letter of the rack ('?' is a blank)
+  subanagram  words using some of the letters of the rack
+  pattern     words matching a pattern ('?' is one letter, '*' any letters)
+  hooks       front and back hooks of a word
+  lookup      whether a word is valid, with its definition and probability
+
+flags:`
+
+func words(args []string) error {
+	fs := flag.NewFlagSet("words", flag.ContinueOnError)
+	lex := addLexiconFlags(fs)
+	limit := fs.Int("limit", 0, "maximum number of results (0 for no limit)")
+	minLen := fs.Int("min", 2, "minimum word length for subanagrams")
+	defsFile := fs.String("defs", "", "file of \"WORD definition\" lines")
+	compareFile := fs.String("compare", "", "lexicon file to compare "+
+		"against; words missing from it are marked with '#'")
+	byProb := fs.Bool("prob", false, "order results by probability")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), wordsUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errors.New("words: expected a query and its letters")
+	}
+	query, letters := fs.Arg(0), strings.ToUpper(fs.Arg(1))
+	// Ranking walks the whole lexicon, so only do it when the output uses
+	// it.
+	rank := *byProb || query == "lookup"
+	root, err := loadLexicon(lex, *defsFile, *compareFile, rank)
+	if err != nil {
+		return err
+	}
+	var ws []dict.Word
+	switch query {
+	case "anagram":
+		ws = root.Anagrams(letters, *limit)
+	case "subanagram":
+		ws = root.Subanagrams(letters, *minLen, *limit)
+	case "pattern":
+		ws = root.Pattern(letters, *limit)
+	case "hooks":
+		word := dict.Word(letters)
+		fmt.Printf("%s %s %s\n", string(root.FrontHooks(word)), word,
+			string(root.BackHooks(word)))
+		return nil
+	case "lookup":
+		word := dict.Word(letters)
+		if _, ok := root.Lookup(word); !ok {
+			fmt.Printf("%s is not a word\n", word)
+			return nil
+		}
+		ws = []dict.Word{word}
+	default:
+		fs.Usage()
+		return fmt.Errorf("words: unknown query %q", query)
+	}
+	if *byProb {
+		sort.SliceStable(ws, func(i, j int) bool {
+			mi, _ := root.Lookup(ws[i])
+			mj, _ := root.Lookup(ws[j])
+			return mi.Probability > mj.Probability
+		})
+	}
+	printWords(root, ws, rank)
+	return nil
+}
+
+// otherOnly flags words that are missing from the lexicon given by -compare.
+const otherOnly dict.Flags = 1 << iota
+
+func loadLexicon(lex *lexiconFlags, defsFile, compareFile string,
+	rank bool) (*dict.Node, error) {
+	root, err := lex.load()
+	if err != nil {
+		return nil, err
+	}
+	if defsFile != "" {
+		if err := dict.LoadDefinitions(root, defsFile); err != nil {
+			return nil, err
+		}
+	}
+	if compareFile != "" {
+		other, err := dict.Load(compareFile)
+		if err != nil {
+			return nil, err
+		}
+		root.FlagMissing(other, otherOnly)
+	}
+	if rank {
+		root.RankProbabilities(dict.LettersDist())
+	}
+	return root, nil
+}
+
+func printWords(root *dict.Node, ws []dict.Word, rank bool) {
+	for _, w := range ws {
+		m, _ := root.Lookup(w)
+		if m == nil {
+			fmt.Println(w)
+			continue
+		}
+		mark := ""
+		if m.Flags&otherOnly != 0 {
+			mark = "#"
+		}
+		if rank {
+			fmt.Printf("%s%s\t%d\t%s\n", w, mark, m.Rank, m.Definition)
+		} else {
+			fmt.Printf("%s%s\t%s\n", w, mark, m.Definition)
+		}
+	}
+}
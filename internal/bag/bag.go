@@ -8,6 +8,11 @@ import (
 
 type Bag struct {
 	letters []Letter
+	// seeded is set when shuffles use a source of their own, seeded from
+	// seed and how many shuffles came before, instead of the global source.
+	seeded   bool
+	seed     int64
+	shuffles int64
 }
 
 func New() *Bag {
@@ -15,7 +20,14 @@ func New() *Bag {
 	rand.Shuffle(len(ls), func(i, j int) {
 		ls[i], ls[j] = ls[j], ls[i]
 	})
-	return &Bag{ls}
+	return &Bag{letters: ls}
+}
+
+// FromLetters returns a bag that letters are drawn from in the order given.
+func FromLetters(letters []Letter) *Bag {
+	ls := make([]Letter, len(letters))
+	copy(ls, letters)
+	return &Bag{letters: ls}
 }
 
 func (b *Bag) Copy() *Bag {
@@ -23,6 +35,7 @@ func (b *Bag) Copy() *Bag {
 		letters: make([]Letter, len(b.letters)),
 	}
 	copy(b2.letters, b.letters)
+	b2.seeded, b2.seed, b2.shuffles = b.seeded, b.seed, b.shuffles
 	return &b2
 }
 
@@ -32,6 +45,58 @@ func (b *Bag) Draw(n int) []Letter {
 	return ls
 }
 
+// Return puts letters back in b and shuffles it.
+func (b *Bag) Return(letters []Letter) {
+	ls := make([]Letter, 0, len(b.letters)+len(letters))
+	ls = append(append(ls, b.letters...), letters...)
+	swap := func(i, j int) {
+		ls[i], ls[j] = ls[j], ls[i]
+	}
+	if b.seeded {
+		// Spread the seeds of successive shuffles out by the golden ratio.
+		r := rand.New(rand.NewSource(b.seed + b.shuffles*-0x61c8864680b583eb))
+		r.Shuffle(len(ls), swap)
+		b.shuffles++
+	} else {
+		rand.Shuffle(len(ls), swap)
+	}
+	b.letters = ls
+}
+
+// SetSeed makes b shuffle with sources seeded from seed instead of the
+// global one. Copies of b, and b after Restore, go on shuffling as b would
+// have from then.
+func (b *Bag) SetSeed(seed int64) {
+	b.seeded, b.seed, b.shuffles = true, seed, 0
+}
+
+// Letters returns the letters in b in the order they will be drawn.
+func (b *Bag) Letters() []Letter {
+	ls := make([]Letter, len(b.letters))
+	copy(ls, b.letters)
+	return ls
+}
+
+func (b *Bag) Len() int {
+	return len(b.letters)
+}
+
+// State is a snapshot of the contents of a bag and of how it shuffles.
+type State struct {
+	letters  []Letter
+	shuffles int64
+}
+
+// State returns a snapshot of b that Restore can go back to.
+func (b *Bag) State() State {
+	return State{b.letters, b.shuffles}
+}
+
+// Restore puts back the contents b had when s was taken.
+func (b *Bag) Restore(s State) {
+	b.letters, b.shuffles = s.letters, s.shuffles
+}
+
 func (b *Bag) Empty() bool {
 	return len(b.letters) == 0
 }
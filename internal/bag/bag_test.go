@@ -0,0 +1,84 @@
+package bag
+
+import (
+	"github.com/stretchr/testify/assert"
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+	"testing"
+)
+
+func TestNew(t *testing.T) {
+	b := New()
+	assert.Equal(t, len(LettersDist()), b.Len())
+	assert.ElementsMatch(t, LettersDist(), b.Letters())
+}
+
+func TestBag_Draw(t *testing.T) {
+	b := FromLetters([]Letter("ABCDE"))
+	assert.Equal(t, []Letter("AB"), b.Draw(2))
+	assert.Equal(t, []Letter("CDE"), b.Letters())
+	assert.Equal(t, []Letter("CDE"), b.Draw(7))
+	assert.True(t, b.Empty())
+	assert.Empty(t, b.Draw(1))
+}
+
+func TestBag_Copy(t *testing.T) {
+	letters := []Letter("ABC")
+	b := FromLetters(letters)
+	letters[0] = 'Z'
+	b2 := b.Copy()
+	b2.Draw(1)
+	assert.Equal(t, "A,B,C", b.String())
+	assert.Equal(t, "B,C", b2.String())
+}
+
+func TestBag_StateRestore(t *testing.T) {
+	b := FromLetters([]Letter("ABCD"))
+	s := b.State()
+	b.Draw(2)
+	b.Return([]Letter("XY"))
+	b.Restore(s)
+	assert.Equal(t, []Letter("ABCD"), b.Letters())
+}
+
+func TestBag_Return(t *testing.T) {
+	b := FromLetters([]Letter("ABCDEFGH"))
+	b.SetSeed(1)
+	b.Return([]Letter("XYZ"))
+	assert.ElementsMatch(t, []Letter("ABCDEFGHXYZ"), b.Letters())
+
+	// The same seed shuffles the same way.
+	b2 := FromLetters([]Letter("ABCDEFGH"))
+	b2.SetSeed(1)
+	b2.Return([]Letter("XYZ"))
+	assert.Equal(t, b.Letters(), b2.Letters())
+}
+
+func TestBag_SetSeed_Copy(t *testing.T) {
+	b := FromLetters([]Letter("ABCDEFGH"))
+	b.SetSeed(1)
+	b.Return([]Letter("X"))
+	first := b.Letters()
+
+	// A copy shuffles as b would next, and copying leaves b alone.
+	b2 := b.Copy()
+	b2.Return([]Letter("YZ"))
+	b.Return([]Letter("YZ"))
+	assert.Equal(t, b.Letters(), b2.Letters())
+
+	// Each shuffle is different.
+	b3 := FromLetters(first)
+	b3.SetSeed(1)
+	b3.Return([]Letter("YZ"))
+	assert.NotEqual(t, b.Letters(), b3.Letters())
+}
+
+func TestBag_SetSeed_Restore(t *testing.T) {
+	b := FromLetters([]Letter("ABCDEFGH"))
+	b.SetSeed(1)
+	s := b.State()
+	b.Return([]Letter("XYZ"))
+	shuffled := b.Letters()
+	b.Restore(s)
+	b.Return([]Letter("XYZ"))
+	assert.Equal(t, shuffled, b.Letters())
+}
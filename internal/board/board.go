@@ -11,6 +11,7 @@ import (
 type Board struct {
 	staleX *bool
 	staleY *bool
+	hash   *uint64
 	size   int
 	tiles  []*Tile
 }
@@ -21,9 +22,11 @@ func New(size int) *Board {
 	}
 	staleX := false
 	staleY := false
+	var hash uint64
 	b := Board{
 		staleX: &staleX,
 		staleY: &staleY,
+		hash:   &hash,
 		size:   size,
 		tiles:  make([]*Tile, size*size),
 	}
@@ -36,33 +39,24 @@ func New(size int) *Board {
 func (b *Board) Copy() *Board {
 	staleY := *b.staleY
 	staleX := *b.staleX
+	hash := *b.hash
 	tiles := make([]*Tile, len(b.tiles))
 	b2 := Board{
 		staleX: &staleX,
 		staleY: &staleY,
+		hash:   &hash,
 		size:   b.size,
 		tiles:  tiles,
 	}
 	for i, t := range b.tiles {
 		letter := *t.letter
-		var crossCheckX map[Letter]struct{}
-		var crossCheckY map[Letter]struct{}
-		if *t.crossCheckX != nil {
-			crossCheckX = make(map[Letter]struct{}, len(*t.crossCheckX))
-			for k, v := range *t.crossCheckX {
-				crossCheckX[k] = v
-			}
-		}
-		if *t.crossCheckY != nil {
-			crossCheckY = make(map[Letter]struct{}, len(*t.crossCheckY))
-			for k, v := range *t.crossCheckY {
-				crossCheckY[k] = v
-			}
-		}
+		crossCheckX := *t.crossCheckX
+		crossCheckY := *t.crossCheckY
 		tiles[i] = &Tile{
 			letter:      &letter,
 			board:       &b2,
 			i:           i,
+			orig:        t.orig,
 			crossCheckX: &crossCheckX,
 			crossCheckY: &crossCheckY,
 		}
@@ -75,6 +69,7 @@ func (b *Board) Transposed() *Board {
 	b2 := Board{
 		staleX: b.staleY,
 		staleY: b.staleX,
+		hash:   b.hash,
 		size:   b.size,
 		tiles:  tiles,
 	}
@@ -85,23 +80,21 @@ func (b *Board) Transposed() *Board {
 	return &b2
 }
 
+// Hash returns the Zobrist hash of the letters on the board. Boards with the
+// same letters on the same squares have the same hash, however they got
+// there.
+func (b *Board) Hash() uint64 {
+	return *b.hash
+}
+
+// SetYCrossChecks recomputes the stale down cross-checks of the board.
 func (b *Board) SetYCrossChecks(dict *Node) {
 	if !*b.staleY {
 		return
 	}
 	for _, t := range b.tiles {
-		if !t.YAnchor() {
-			*t.crossCheckY = nil
-			continue
-		}
-		m := make(map[Letter]struct{})
-		*t.crossCheckY = m
-		above := t.GatherUp()
-		below := t.GatherDown()
-		for l, n := range dict.Search(above).Edges() {
-			if n.Search(below).Accept() {
-				m[l] = struct{}{}
-			}
+		if t.crossCheckY.stale {
+			t.setYCrossCheck(dict)
 		}
 	}
 	*b.staleY = false
@@ -123,69 +116,63 @@ func (b *Board) SetAcross(row, col int, word Word) {
 	}
 }
 
+// Points returns what m scores on b. It panics if m does not fit on b.
 func (b *Board) Points(m Move) int {
+	if m.Skip || len(m.Word) == 0 && len(m.Tiles) == 0 {
+		return 0
+	}
+	m, err := b.Complete(m)
+	if err != nil {
+		panic(err)
+	}
 	if m.Dir == DirDown {
 		b = b.Transposed()
 		m = m.Transposed()
 	}
-	if m.Skip || len(m.Word) == 0 {
-		return 0
-	}
 
+	start := b.At(m.Row, m.Col)
+	end := start.RightN(len(m.Word) - 1)
+	mainSum := start.gatherPoints((*Tile).Left) +
+		end.gatherPoints((*Tile).Right)
+	mainFactor := 1
 	var points int
-	sumPoints := func(words ...Word) int {
-		var sum int
-		for _, w := range words {
-			for _, l := range w {
-				sum += Letter(l).Points()
-			}
-		}
-		return sum
-	}
-
-	// Sum across word.
-	t := b.At(m.Row, m.Col)
-	left := t.GatherLeft()
-	right := t.RightN(len(m.Word) - 1).GatherRight()
-	// If the across word size is == 1, then it must simply be an extension to
-	// a down word. There are no words of length == 1, so don't count them.
-	if len(left)+len(m.Word)+len(right) > 1 {
-		wordFactor := 1
-		var midPoints int
-		for i, l := range m.Word {
-			factor, word := t.RightN(i).Premium()
-			lp := Letter(l).Points()
-			if word {
-				wordFactor *= factor
-				midPoints += lp
-			} else {
-				midPoints += factor * lp
-			}
-		}
-		points += wordFactor * (midPoints + sumPoints(left, right))
-	}
-
-	// Sum down word(s).
-	for i, l := range m.Word {
-		t := t.RightN(i)
+	tiles := m.Tiles
+	for i := range m.Word {
+		t := start.RightN(i)
 		if !t.Empty() {
+			mainSum += t.Points()
 			continue
 		}
-		above := t.GatherUp()
-		below := t.GatherDown()
-		if len(above)+len(below) == 0 {
-			continue
+		p := tiles[0]
+		tiles = tiles[1:]
+		lp := 0
+		if !p.Blank {
+			lp = p.Letter.Points()
 		}
-		wordFactor := 1
-		midPoints := Letter(l).Points()
-		if factor, word := t.Premium(); word {
-			wordFactor = factor
+		factor, word := t.Premium()
+		crossFactor, crossSum := 1, lp
+		if word {
+			mainFactor *= factor
+			crossFactor = factor
 		} else {
-			midPoints *= factor
+			lp *= factor
+			crossSum = lp
 		}
-		points += wordFactor * (midPoints + sumPoints(above, below))
+		mainSum += lp
+
+		// Sum the down word through t, if any.
+		if !t.Up().Empty() || !t.Down().Empty() {
+			crossSum += t.gatherPoints((*Tile).Up) +
+				t.gatherPoints((*Tile).Down)
+			points += crossFactor * crossSum
+		}
+	}
+	// If the across word size is == 1, then it must simply be an extension to
+	// a down word. There are no words of length == 1, so don't count them.
+	if !start.Left().Empty() || !end.Right().Empty() || len(m.Word) > 1 {
+		points += mainFactor * mainSum
 	}
-	if len(m.Word) == rules.RackSize {
+	if len(m.Tiles) == rules.RackSize {
 		points += rules.BingoPremium
 	}
 	return points
@@ -233,19 +220,16 @@ func (b *Board) String() string {
 	var buf strings.Builder
 	buf.WriteString("  ")
 	for i := 0; i < b.size; i++ {
-		buf.WriteString(fmt.Sprintf("%x", i))
-		if i < b.size-1 {
-			buf.WriteRune(' ')
-		} else {
-			buf.WriteRune('\n')
-		}
+		buf.WriteRune(' ')
+		buf.WriteRune(rune('A' + i))
 	}
+	buf.WriteRune('\n')
 	for i, t := range b.tiles {
 		if i%b.size == 0 {
 			if i > 0 {
 				buf.WriteRune('\n')
 			}
-			buf.WriteString(fmt.Sprintf("%x ", i/b.size))
+			buf.WriteString(fmt.Sprintf("%2d ", i/b.size+1))
 		} else {
 			buf.WriteRune(' ')
 		}
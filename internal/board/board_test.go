@@ -5,15 +5,15 @@ import (
 	. "github.com/tmazeika/scrabble-go/internal/dict"
 	"github.com/tmazeika/scrabble-go/internal/move"
 	"github.com/tmazeika/scrabble-go/internal/rules"
+	"math/rand"
 	"testing"
 )
 
 func abcBoard() *Board {
-	b := New(3)
-	for i := 0; i < 3*3; i++ {
-		b.AtIdx(i).Set(Letter('A' + i))
-	}
-	return b
+	return MustFromString(`
+ABC
+DEF
+GHI`)
 }
 
 func TestBoard_Copy(t *testing.T) {
@@ -76,3 +76,117 @@ func TestBoard_Points(t *testing.T) {
 	assert.Equal(t, 16, b.Points(m5))
 	b.SetAcross(m5.Row, m5.Col, m5.Word)
 }
+
+func TestBoard_SetYCrossChecks(t *testing.T) {
+	d := NewNode()
+	words := []Word{"AT", "TA", "EAT", "TEA", "ATE", "ETA", "EATS", "SEAT",
+		"TEAS", "EAST", "SATE", "CAT", "CATS", "SCAT", "ACE", "ACES"}
+	for _, w := range words {
+		d.Insert(w)
+	}
+	r := rand.New(rand.NewSource(1))
+	for game := 0; game < 20; game++ {
+		b := New(rules.BoardSize)
+		for m := 0; m < 30; m++ {
+			bm := b
+			if r.Intn(2) == 0 {
+				bm = b.Transposed()
+			}
+			w := words[r.Intn(len(words))]
+			row := r.Intn(rules.BoardSize)
+			col := r.Intn(rules.BoardSize - len(w) + 1)
+			bm.SetAcross(row, col, w)
+			if r.Intn(4) == 0 {
+				b.AtIdx(r.Intn(len(b.tiles))).Set(0)
+			}
+			b.SetYCrossChecks(d)
+			b.Transposed().SetYCrossChecks(d)
+
+			full := b.Copy()
+			for _, t := range full.tiles {
+				t.crossCheckX.stale = true
+				t.crossCheckY.stale = true
+			}
+			*full.staleX = true
+			*full.staleY = true
+			full.SetYCrossChecks(d)
+			full.Transposed().SetYCrossChecks(d)
+			for i, tile := range b.tiles {
+				assert.Equal(t, *full.tiles[i].crossCheckX, *tile.crossCheckX)
+				assert.Equal(t, *full.tiles[i].crossCheckY, *tile.crossCheckY)
+			}
+		}
+	}
+}
+
+func TestBoard_UndoMove(t *testing.T) {
+	d := NewNode()
+	words := []Word{"AT", "TA", "EAT", "TEA", "EATS", "SEAT", "CAT", "ACES"}
+	for _, w := range words {
+		d.Insert(w)
+	}
+	setCrossChecks := func(b *Board) {
+		b.SetYCrossChecks(d)
+		b.Transposed().SetYCrossChecks(d)
+	}
+	r := rand.New(rand.NewSource(1))
+	for game := 0; game < 20; game++ {
+		b := New(rules.BoardSize)
+		var snapshots []*Board
+		var undos []Undo
+		for i := 0; i < 20; i++ {
+			w := words[r.Intn(len(words))]
+			m := move.Move{
+				Row:  r.Intn(rules.BoardSize),
+				Col:  r.Intn(rules.BoardSize - len(w) + 1),
+				Word: w,
+			}
+			if r.Intn(2) == 0 {
+				m = m.Transposed()
+			}
+			if _, err := b.Complete(m); err != nil {
+				continue
+			}
+			snapshots = append(snapshots, b.Copy())
+			undos = append(undos, b.PlaceMove(m))
+			setCrossChecks(b)
+		}
+		for i := len(undos) - 1; i >= 0; i-- {
+			b.UndoMove(undos[i])
+			setCrossChecks(b)
+			setCrossChecks(snapshots[i])
+			for j, tile := range b.tiles {
+				want := snapshots[i].tiles[j]
+				assert.Equal(t, *want.letter, *tile.letter)
+				assert.Equal(t, *want.crossCheckX, *tile.crossCheckX)
+				assert.Equal(t, *want.crossCheckY, *tile.crossCheckY)
+			}
+		}
+	}
+}
+
+func TestBoard_Hash(t *testing.T) {
+	b1 := New(rules.BoardSize)
+	b2 := New(rules.BoardSize)
+	assert.Equal(t, uint64(0), b1.Hash())
+
+	u1 := b1.PlaceMove(move.Move{Row: 7, Col: 7, Word: "CAT"})
+	b1.PlaceMove(move.Move{Row: 6, Col: 8, Dir: move.DirDown, Word: "TAX"})
+	b2.Transposed().SetAcross(8, 6, "TAX")
+	b2.SetAcross(7, 7, "C")
+	b2.At(7, 9).Set('T')
+	assert.Equal(t, b1.Hash(), b2.Hash())
+	assert.Equal(t, b1.Hash(), b1.Copy().Hash())
+	assert.Equal(t, b1.Hash(), b1.Transposed().Hash())
+
+	b2.At(7, 9).Set('S')
+	assert.NotEqual(t, b1.Hash(), b2.Hash())
+	b2.At(7, 9).Set('T')
+	assert.Equal(t, b1.Hash(), b2.Hash())
+
+	b1.UndoMove(b1.PlaceMove(move.Move{Row: 5, Col: 8, Dir: move.DirDown,
+		Word: "STAX"}))
+	assert.Equal(t, b2.Hash(), b1.Hash())
+	b1.UndoMove(u1)
+	assert.NotEqual(t, b2.Hash(), b1.Hash())
+}
@@ -0,0 +1,42 @@
+package board
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/tmazeika/scrabble-go/internal/fixture"
+	"github.com/tmazeika/scrabble-go/internal/move"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestBoard_Points_Fixtures scores the moves in testdata/points. Each file
+// is a board and then a line per move, in notation, with what it scores.
+func TestBoard_Points_Fixtures(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "points", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, paths)
+	for _, path := range paths {
+		paragraphs, err := fixture.Read(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(paragraphs) != 2 {
+			t.Fatalf("%s: want a board and moves", path)
+		}
+		b, err := FromString(paragraphs[0])
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+		for _, line := range strings.Split(paragraphs[1], "\n") {
+			i := strings.LastIndexByte(line, ' ')
+			m, err := move.Parse(line[:i])
+			assert.Nil(t, err, "%s: %s", path, line)
+			points, err := strconv.Atoi(line[i+1:])
+			assert.Nil(t, err, "%s: %s", path, line)
+			assert.Equal(t, points, b.Points(m), "%s: %s", path, line)
+		}
+	}
+}
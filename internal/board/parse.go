@@ -0,0 +1,68 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+	"strconv"
+	"strings"
+)
+
+// FromString reads a board written out by String. The row and column headers
+// may be left out, as may the spaces between squares. Empty squares are '-'
+// or '.', and lowercase letters are blanks. The board is as big as the number
+// of rows.
+func FromString(s string) (*Board, error) {
+	var rows [][]string
+	for _, line := range strings.Split(s, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) == 1 {
+			fields = strings.Split(fields[0], "")
+		}
+		rows = append(rows, fields)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("no board")
+	}
+	// The column header is one shorter than the rows below it, which start
+	// with their row header.
+	if len(rows) > 1 && len(rows[0]) < len(rows[1]) {
+		rows = rows[1:]
+	}
+	size := len(rows)
+	b := New(size)
+	for row, fields := range rows {
+		if len(fields) == size+1 && fields[0] == strconv.Itoa(row+1) {
+			fields = fields[1:]
+		}
+		if len(fields) != size {
+			return nil, fmt.Errorf("row %d has %d squares, not %d",
+				row, len(fields), size)
+		}
+		for col, f := range fields {
+			l := Letter(f[0])
+			switch {
+			case len(f) != 1:
+				return nil, fmt.Errorf("bad square %q at (%d,%d)", f, row, col)
+			case l == '-' || l == '.':
+			case 'A' <= l.Upper() && l.Upper() <= 'Z':
+				b.At(row, col).Set(l)
+			default:
+				return nil, fmt.Errorf("bad square %q at (%d,%d)", f, row, col)
+			}
+		}
+	}
+	return b, nil
+}
+
+// MustFromString is like FromString but panics if s is not a board.
+func MustFromString(s string) *Board {
+	b, err := FromString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
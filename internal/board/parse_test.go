@@ -0,0 +1,42 @@
+package board
+
+import (
+	"github.com/stretchr/testify/assert"
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/rules"
+	"testing"
+)
+
+func TestFromString(t *testing.T) {
+	b := New(rules.BoardSize)
+	b.SetAcross(7, 5, "HoRN")
+	b.SetDown(5, 7, "FARM")
+	b.SetAcross(14, 11, "QUIz")
+	b2, err := FromString(b.String())
+	assert.Nil(t, err)
+	assert.Equal(t, b.String(), b2.String())
+	assert.Equal(t, b.Hash(), b2.Hash())
+	assert.True(t, b2.At(14, 14).Blank())
+
+	b, err = FromString(`
+. a -
+D . F
+- H I
+`)
+	assert.Nil(t, err)
+	assert.Equal(t, "   A B C\n 1 - a -\n 2 D - F\n 3 - H I", b.String())
+	assert.Equal(t, Letter('A'), b.At(0, 1).Letter())
+	b2, err = FromString("-a-\nD-F\n-HI")
+	assert.Nil(t, err)
+	assert.Equal(t, b.String(), b2.String())
+	b2, err = FromString("1 - a -\n2 D - F\n3 - H I")
+	assert.Nil(t, err)
+	assert.Equal(t, b.String(), b2.String())
+
+	for _, s := range []string{"", "AB\nCD\nEF", "ABC\nDEF\nGH-I",
+		"A1\nBC", "AB\nC?"} {
+		_, err := FromString(s)
+		assert.NotNil(t, err, s)
+	}
+	assert.Panics(t, func() { MustFromString("AB") })
+}
@@ -0,0 +1,201 @@
+package board
+
+import (
+	"errors"
+	"fmt"
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+	. "github.com/tmazeika/scrabble-go/internal/move"
+	"sort"
+)
+
+// Complete fills in m's Word from its Tiles, or its Tiles from its Word,
+// using the letters on b. When m has tiles, they decide where the move goes;
+// a lowercase letter in a Word without tiles is played with a blank. Letters
+// in Word on occupied squares must match the board, or be PlayThrough. The
+// returned move's Word is uppercase and spelled out in full.
+func (b *Board) Complete(m Move) (Move, error) {
+	if m.Skip {
+		return m, nil
+	}
+	if len(m.Tiles) > 0 {
+		m2, err := b.fromTiles(m.Tiles)
+		if err != nil {
+			return Move{}, err
+		}
+		if m.Word != "" && (!spells(m.Word, m2.Word) || m2.Row != m.Row ||
+			m2.Col != m.Col || m2.Dir != m.Dir) {
+			return Move{}, fmt.Errorf("tiles do not spell out %v", m)
+		}
+		return m2, nil
+	}
+	transposed := m.Dir == DirDown
+	bm := b
+	if transposed {
+		bm = b.Transposed()
+		m = m.Transposed()
+	}
+	if !bm.FitsAcross(m.Row, m.Col, len(m.Word)) {
+		return Move{}, fmt.Errorf("move would fall off the board: %v", m)
+	}
+	m.Tiles = []Placement{}
+	start := bm.At(m.Row, m.Col)
+	word := make([]Letter, len(m.Word))
+	for i, r := range m.Word {
+		t, l := start.RightN(i), Letter(r)
+		switch {
+		case !t.Empty():
+			if l != PlayThrough && l.Upper() != t.Letter() {
+				return Move{}, fmt.Errorf("%c is not on the board at "+
+					"(%d,%d): %v", l, t.Row(), t.Col(), m)
+			}
+			word[i] = t.Letter()
+		case l == PlayThrough:
+			return Move{}, fmt.Errorf("no letter on the board at (%d,%d) to "+
+				"play through: %v", t.Row(), t.Col(), m)
+		default:
+			m.Tiles = append(m.Tiles, Placement{
+				Row:    t.Row(),
+				Col:    t.Col(),
+				Letter: l.Upper(),
+				Blank:  l.IsLower(),
+			})
+			word[i] = l.Upper()
+		}
+	}
+	m.Word = Word(string(word))
+	if transposed {
+		m = m.Transposed()
+	}
+	return m, nil
+}
+
+// spells reports whether word, which may be in lowercase or have
+// PlayThrough letters, is written the same as full.
+func spells(word, full Word) bool {
+	if len(word) != len(full) {
+		return false
+	}
+	for i := range word {
+		l := Letter(word[i])
+		if l != PlayThrough && l.Upper() != Letter(full[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// fromTiles works out the move that puts down tiles, which must be in a
+// single line with only letters on b between them. The move's word takes in
+// any letters on b directly before or after the tiles. A single tile is
+// played across if that makes a word of more than one letter, and down
+// otherwise.
+func (b *Board) fromTiles(tiles []Placement) (Move, error) {
+	tiles = append([]Placement(nil), tiles...)
+	sort.Slice(tiles, func(i, j int) bool {
+		if tiles[i].Row != tiles[j].Row {
+			return tiles[i].Row < tiles[j].Row
+		}
+		return tiles[i].Col < tiles[j].Col
+	})
+	for _, p := range tiles {
+		if p.Row < 0 || p.Row >= b.size || p.Col < 0 || p.Col >= b.size {
+			return Move{}, fmt.Errorf("tile %c at (%d,%d) is off the board",
+				p.Letter, p.Row, p.Col)
+		}
+		if !b.At(p.Row, p.Col).Empty() {
+			return Move{}, fmt.Errorf("tile %c at (%d,%d) is on an occupied "+
+				"square", p.Letter, p.Row, p.Col)
+		}
+	}
+	first, last := tiles[0], tiles[len(tiles)-1]
+	var dir Dir
+	switch {
+	case len(tiles) == 1:
+		t := b.At(first.Row, first.Col)
+		if t.Left().Empty() && t.Right().Empty() &&
+			(!t.Up().Empty() || !t.Down().Empty()) {
+			dir = DirDown
+		}
+	case first.Row == last.Row:
+		dir = DirAcross
+	case first.Col == last.Col:
+		dir = DirDown
+	default:
+		return Move{}, errors.New("tiles are not in a single line")
+	}
+	bm := b
+	if dir == DirDown {
+		bm = b.Transposed()
+		for i := range tiles {
+			tiles[i].Row, tiles[i].Col = tiles[i].Col, tiles[i].Row
+		}
+		first, last = tiles[0], tiles[len(tiles)-1]
+	}
+	if first.Row != last.Row {
+		return Move{}, errors.New("tiles are not in a single line")
+	}
+
+	start := bm.At(first.Row, first.Col)
+	for !start.Left().Empty() {
+		start = start.Left()
+	}
+	m := Move{
+		Row:   start.Row(),
+		Col:   start.Col(),
+		Dir:   DirAcross,
+		Tiles: tiles,
+	}
+	word := make([]Letter, 0, len(tiles))
+	i := 0
+	for t := start; t != nil; t = t.Right() {
+		if i < len(tiles) && t.Col() == tiles[i].Col {
+			word = append(word, tiles[i].Letter.Upper())
+			i++
+		} else if !t.Empty() {
+			word = append(word, t.Letter())
+		} else if i < len(tiles) {
+			return Move{}, errors.New("tiles are not connected")
+		} else {
+			break
+		}
+	}
+	m.Word = Word(string(word))
+	if dir == DirDown {
+		m = m.Transposed()
+	}
+	return m, nil
+}
+
+// FormedWords returns the words that m makes on b: the word along m's
+// direction and any words across it, leaving out words of one letter.
+func (b *Board) FormedWords(m Move) []Word {
+	if m.Skip {
+		return nil
+	}
+	m, err := b.Complete(m)
+	if err != nil {
+		panic(err)
+	}
+	if m.Dir == DirDown {
+		b = b.Transposed()
+		m = m.Transposed()
+	}
+	var words []Word
+	start := b.At(m.Row, m.Col)
+	main := start.GatherLeft() + m.Word +
+		start.RightN(len(m.Word)-1).GatherRight()
+	if len(main) > 1 {
+		words = append(words, main)
+	}
+	for i, r := range m.Word {
+		t := start.RightN(i)
+		if !t.Empty() {
+			continue
+		}
+		cross := t.GatherUp().Append(Letter(r)) + t.GatherDown()
+		if len(cross) > 1 {
+			words = append(words, cross)
+		}
+	}
+	return words
+}
@@ -0,0 +1,116 @@
+package board
+
+import (
+	"github.com/stretchr/testify/assert"
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/move"
+	"github.com/tmazeika/scrabble-go/internal/rules"
+	"testing"
+)
+
+func placementBoard() *Board {
+	b := New(rules.BoardSize)
+	b.SetAcross(7, 5, "HoRN")
+	return b
+}
+
+func TestBoard_Complete(t *testing.T) {
+	b := placementBoard()
+	assert.Equal(t, Letter('O'), b.At(7, 6).Letter())
+	assert.True(t, b.At(7, 6).Blank())
+
+	// From a word: the tiles are the empty squares it covers.
+	m, err := b.Complete(move.Move{Row: 5, Col: 7, Dir: move.DirDown,
+		Word: "FaRM"})
+	assert.Nil(t, err)
+	assert.Equal(t, move.Move{Row: 5, Col: 7, Dir: move.DirDown, Word: "FARM",
+		Tiles: []move.Placement{
+			{Row: 5, Col: 7, Letter: 'F'},
+			{Row: 6, Col: 7, Letter: 'A', Blank: true},
+			{Row: 8, Col: 7, Letter: 'M'},
+		}}, m)
+
+	// From tiles: the word takes in the letters around them.
+	m2, err := b.Complete(move.Move{Tiles: []move.Placement{
+		{Row: 8, Col: 7, Letter: 'M'},
+		{Row: 6, Col: 7, Letter: 'A', Blank: true},
+		{Row: 5, Col: 7, Letter: 'F'},
+	}})
+	assert.Nil(t, err)
+	assert.Equal(t, m, m2)
+
+	m, err = b.Complete(move.Move{Tiles: []move.Placement{
+		{Row: 7, Col: 9, Letter: 'S'},
+	}})
+	assert.Nil(t, err)
+	assert.Equal(t, move.Move{Row: 7, Col: 5, Dir: move.DirAcross,
+		Word: "HORNS", Tiles: []move.Placement{
+			{Row: 7, Col: 9, Letter: 'S'},
+		}}, m)
+	m, err = b.Complete(move.Move{Tiles: []move.Placement{
+		{Row: 8, Col: 5, Letter: 'A'},
+	}})
+	assert.Nil(t, err)
+	assert.Equal(t, move.DirDown, m.Dir)
+	assert.Equal(t, Word("HA"), m.Word)
+	_, err = b.Complete(move.Move{Row: 7, Col: 5, Dir: move.DirDown,
+		Word: "HORNS", Tiles: []move.Placement{
+			{Row: 7, Col: 9, Letter: 'S'},
+		}})
+	assert.NotNil(t, err)
+
+	_, err = b.Complete(move.Move{Tiles: []move.Placement{
+		{Row: 5, Col: 7, Letter: 'F'},
+		{Row: 8, Col: 7, Letter: 'M'},
+	}})
+	assert.NotNil(t, err)
+	_, err = b.Complete(move.Move{Tiles: []move.Placement{
+		{Row: 5, Col: 7, Letter: 'F'},
+		{Row: 6, Col: 8, Letter: 'M'},
+	}})
+	assert.NotNil(t, err)
+	_, err = b.Complete(move.Move{Tiles: []move.Placement{
+		{Row: 7, Col: 5, Letter: 'F'},
+	}})
+	assert.NotNil(t, err)
+	_, err = b.Complete(move.Move{Row: 7, Col: 13, Word: "HORN"})
+	assert.NotNil(t, err)
+
+	// Letters on the board can be left out with PlayThrough, but must
+	// otherwise match.
+	m, err = b.Complete(move.Move{Row: 7, Col: 5, Word: "..RNs"})
+	assert.Nil(t, err)
+	assert.Equal(t, Word("HORNS"), m.Word)
+	assert.Equal(t, []move.Placement{{Row: 7, Col: 9, Letter: 'S',
+		Blank: true}}, m.Tiles)
+	_, err = b.Complete(move.Move{Row: 7, Col: 5, Word: "HARNS"})
+	assert.NotNil(t, err)
+	_, err = b.Complete(move.Move{Row: 7, Col: 5, Word: "HORN."})
+	assert.NotNil(t, err)
+}
+
+func TestBoard_FormedWords(t *testing.T) {
+	b := placementBoard()
+	b.SetDown(5, 7, "FARM")
+	assert.Equal(t, []Word{"PASTE", "FARMS"},
+		b.FormedWords(move.Move{Row: 9, Col: 5, Word: "PASTE"}))
+	assert.Equal(t, []Word{"FARMS"},
+		b.FormedWords(move.Move{Row: 5, Col: 7, Dir: move.DirDown,
+			Word: "FARMS"}))
+}
+
+func TestBoard_Points_Blanks(t *testing.T) {
+	b := placementBoard()
+	// HoRN: the blank O scores nothing.
+	assert.Equal(t, 7, b.Points(move.Move{Row: 7, Col: 5, Word: "HORNS",
+		Tiles: []move.Placement{{Row: 7, Col: 9, Letter: 'S'}}}))
+	// A blank on a double letter square still scores nothing.
+	assert.Equal(t, 2, b.Points(move.Move{Row: 6, Col: 6, Dir: move.DirDown,
+		Word: "hOE"}))
+	assert.Equal(t, 10, b.Points(move.Move{Row: 6, Col: 6, Dir: move.DirDown,
+		Word: "HOE"}))
+	// Bingos are seven tiles from the rack, whatever the word's length.
+	// SaTIRES makes HE and oS down too.
+	assert.Equal(t, 8+5+2+rules.BingoPremium,
+		b.Points(move.Move{Row: 8, Col: 0, Word: "SaTIRES"}))
+}
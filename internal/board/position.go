@@ -0,0 +1,152 @@
+package board
+
+import (
+	"fmt"
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+	. "github.com/tmazeika/scrabble-go/internal/move"
+	"strings"
+)
+
+// SetDown is SetAcross for a word read downwards from row and col.
+func (b *Board) SetDown(row, col int, word Word) {
+	b.Transposed().SetAcross(col, row, word)
+}
+
+// Place puts the letters of m's word on b, in either direction, without
+// checking it against any rules or lexicon. Lowercase letters are blanks.
+// Letters that land on occupied squares must match what is there, and b is
+// left unchanged if they do not.
+func (b *Board) Place(m Move) error {
+	if m.Skip {
+		return nil
+	}
+	m, err := b.Complete(m)
+	if err != nil {
+		return err
+	}
+	for _, p := range m.Tiles {
+		l := p.Letter
+		if p.Blank {
+			l = l.Lower()
+		}
+		b.At(p.Row, p.Col).Set(l)
+	}
+	return nil
+}
+
+// Clear empties the square at row and col.
+func (b *Board) Clear(row, col int) {
+	b.At(row, col).Set(0)
+}
+
+// PositionError lists what is wrong with a position.
+type PositionError struct {
+	// InvalidWords are the words on the board that are not in the lexicon,
+	// as moves saying where they are.
+	InvalidWords []Move
+	// Regions holds the letters of each group of connected letters, if there
+	// is more than one.
+	Regions [][]Placement
+	// EmptyCenter is set when there are letters on the board but none on the
+	// center square.
+	EmptyCenter bool
+}
+
+func (e *PositionError) Error() string {
+	var problems []string
+	if len(e.InvalidWords) > 0 {
+		words := make([]string, len(e.InvalidWords))
+		for i, m := range e.InvalidWords {
+			words[i] = m.String()
+		}
+		problems = append(problems, "invalid words "+strings.Join(words, ", "))
+	}
+	if len(e.Regions) > 0 {
+		problems = append(problems,
+			fmt.Sprintf("%d disconnected regions", len(e.Regions)))
+	}
+	if e.EmptyCenter {
+		problems = append(problems, "empty center square")
+	}
+	return "bad position: " + strings.Join(problems, "; ")
+}
+
+// Check reports everything wrong with the position on b as a
+// *PositionError: words not in dict, letters not connected to the rest, and
+// an empty center square. An empty board is fine.
+func (b *Board) Check(dict *Node) error {
+	var e PositionError
+	for _, m := range b.Words() {
+		if !dict.Search(m.Word).Accept() {
+			e.InvalidWords = append(e.InvalidWords, m)
+		}
+	}
+	regions := b.regions()
+	if len(regions) > 1 {
+		e.Regions = regions
+	}
+	e.EmptyCenter = len(regions) > 0 && b.Center().Empty()
+	if len(e.InvalidWords) == 0 && len(e.Regions) == 0 && !e.EmptyCenter {
+		return nil
+	}
+	return &e
+}
+
+// Words returns every word of more than one letter on b, across words first,
+// each as a move saying where it is.
+func (b *Board) Words() []Move {
+	moves := b.acrossWords()
+	for _, m := range b.Transposed().acrossWords() {
+		moves = append(moves, m.Transposed())
+	}
+	return moves
+}
+
+func (b *Board) acrossWords() []Move {
+	var moves []Move
+	for row := 0; row < b.size; row++ {
+		for col := 0; col < b.size; col++ {
+			t := b.At(row, col)
+			if t.Empty() || !t.Left().Empty() {
+				continue
+			}
+			if w := Word("").Append(t.Letter()) + t.GatherRight(); len(w) > 1 {
+				moves = append(moves, Move{Row: row, Col: col, Word: w})
+			}
+		}
+	}
+	return moves
+}
+
+// regions returns the letters on b grouped by which are connected.
+func (b *Board) regions() [][]Placement {
+	seen := make([]bool, len(b.tiles))
+	var regions [][]Placement
+	for i, t := range b.tiles {
+		if t.Empty() || seen[i] {
+			continue
+		}
+		var region []Placement
+		stack := []*Tile{t}
+		seen[i] = true
+		for len(stack) > 0 {
+			cur := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			region = append(region, Placement{
+				Row:    cur.Row(),
+				Col:    cur.Col(),
+				Letter: cur.Letter(),
+				Blank:  cur.Blank(),
+			})
+			for _, next := range []*Tile{cur.Up(), cur.Down(), cur.Left(),
+				cur.Right()} {
+				if !next.Empty() && !seen[next.i] {
+					seen[next.i] = true
+					stack = append(stack, next)
+				}
+			}
+		}
+		regions = append(regions, region)
+	}
+	return regions
+}
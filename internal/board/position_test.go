@@ -0,0 +1,70 @@
+package board
+
+import (
+	"github.com/stretchr/testify/assert"
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/move"
+	"github.com/tmazeika/scrabble-go/internal/rules"
+	"testing"
+)
+
+func TestBoard_Place(t *testing.T) {
+	b := New(rules.BoardSize)
+	assert.Nil(t, b.Place(move.Move{Row: 7, Col: 5, Word: "HoRN"}))
+	assert.Nil(t, b.Place(move.Move{Row: 5, Col: 7, Dir: move.DirDown,
+		Word: "FARM"}))
+	assert.Equal(t, Word("FARM"), b.At(4, 7).GatherDown())
+	assert.True(t, b.At(7, 6).Blank())
+	hash := b.Hash()
+
+	assert.NotNil(t, b.Place(move.Move{Row: 5, Col: 7, Dir: move.DirDown,
+		Word: "FIRM"}))
+	assert.NotNil(t, b.Place(move.Move{Row: 7, Col: 13, Word: "HORN"}))
+	assert.Equal(t, hash, b.Hash())
+
+	b2 := New(rules.BoardSize)
+	b2.SetAcross(7, 5, "HoRN")
+	b2.SetDown(5, 7, "FARM")
+	assert.Equal(t, b.String(), b2.String())
+	assert.Equal(t, hash, b2.Hash())
+
+	b2.Clear(8, 7)
+	assert.True(t, b2.At(8, 7).Empty())
+	assert.Equal(t, Word("FAR"), b2.At(4, 7).GatherDown())
+}
+
+func TestBoard_Check(t *testing.T) {
+	d := NewNode()
+	for _, w := range []Word{"HORN", "FARM", "AT"} {
+		d.Insert(w)
+	}
+	b := New(rules.BoardSize)
+	assert.Nil(t, b.Check(d))
+	b.SetAcross(7, 5, "HoRN")
+	b.SetDown(5, 7, "FARM")
+	assert.Nil(t, b.Check(d))
+
+	b.SetAcross(0, 0, "AT")
+	b.SetAcross(12, 3, "TAX")
+	b.SetDown(8, 8, "OX")
+	err := b.Check(d)
+	assert.IsType(t, &PositionError{}, err)
+	e := err.(*PositionError)
+	assert.Equal(t, []move.Move{
+		{Row: 8, Col: 7, Word: "MO"},
+		{Row: 12, Col: 3, Word: "TAX"},
+		{Row: 7, Col: 8, Dir: move.DirDown, Word: "NOX"},
+	}, e.InvalidWords)
+	assert.Len(t, e.Regions, 3)
+	assert.Equal(t, []move.Placement{
+		{Row: 0, Col: 0, Letter: 'A'},
+		{Row: 0, Col: 1, Letter: 'T'},
+	}, e.Regions[0])
+	assert.False(t, e.EmptyCenter)
+	assert.Equal(t, "bad position: invalid words 9H MO, 13D TAX, I8 NOX; "+
+		"3 disconnected regions", err.Error())
+
+	b = New(rules.BoardSize)
+	b.SetAcross(0, 0, "AT")
+	assert.Equal(t, &PositionError{EmptyCenter: true}, b.Check(d))
+}
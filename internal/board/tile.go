@@ -2,26 +2,38 @@ package board
 
 import (
 	. "github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/zobrist"
 	"strings"
 )
 
 type Tile struct {
-	letter      *Letter
-	board       *Board
-	i           int
-	crossCheckX *map[Letter]struct{}
-	crossCheckY *map[Letter]struct{}
+	letter *Letter
+	board  *Board
+	i      int
+	// orig is the index of the tile on the untransposed board.
+	orig        int
+	crossCheckX *crossCheck
+	crossCheckY *crossCheck
+}
+
+// crossCheck is the set of letters that can go on a tile without forming an
+// invalid word in one orientation. It is stale when a nearby letter changed
+// since it was last computed.
+type crossCheck struct {
+	letters LetterSet
+	stale   bool
 }
 
 func newTile(letter Letter, board *Board, i int) *Tile {
-	var nilCrossCheckX map[Letter]struct{} = nil
-	var nilCrossCheckY map[Letter]struct{} = nil
+	crossCheckX := crossCheck{letters: AllLetters}
+	crossCheckY := crossCheck{letters: AllLetters}
 	return &Tile{
 		letter:      &letter,
 		board:       board,
 		i:           i,
-		crossCheckX: &nilCrossCheckX,
-		crossCheckY: &nilCrossCheckY,
+		orig:        i,
+		crossCheckX: &crossCheckX,
+		crossCheckY: &crossCheckY,
 	}
 }
 
@@ -30,23 +42,85 @@ func (t *Tile) transposed(board *Board, i int) *Tile {
 		letter:      t.letter,
 		board:       board,
 		i:           i,
+		orig:        t.orig,
 		crossCheckX: t.crossCheckY,
 		crossCheckY: t.crossCheckX,
 	}
 }
 
+// Letter returns the letter on t, in uppercase even if it is a blank.
 func (t *Tile) Letter() Letter {
-	return *t.letter
+	return (*t.letter).Upper()
+}
+
+// Blank reports whether the letter on t is a blank.
+func (t *Tile) Blank() bool {
+	return (*t.letter).IsLower()
 }
 
+// Points returns what the letter on t is worth, which is nothing for a
+// blank.
+func (t *Tile) Points() int {
+	if t.Empty() || t.Blank() {
+		return 0
+	}
+	return t.Letter().Points()
+}
+
+// Set puts letter on t, or clears t if letter is 0. A lowercase letter is a
+// blank. Only the cross-checks that depend on t are marked stale.
 func (t *Tile) Set(letter Letter) {
+	*t.board.hash ^= t.key() ^ zobristKey(t.orig, letter)
 	*t.letter = letter
-	*t.crossCheckX = nil
-	*t.crossCheckY = nil
+	t.dependents(func(c *crossCheck) {
+		c.stale = true
+	})
 	*t.board.staleX = true
 	*t.board.staleY = true
 }
 
+// dependents calls fn with every cross-check that depends on the letter on t:
+// those of t itself and of the empty tiles at each end of the runs of letters
+// leading away from t.
+func (t *Tile) dependents(fn func(*crossCheck)) {
+	fn(t.crossCheckX)
+	fn(t.crossCheckY)
+	if end := t.runEnd((*Tile).Up); end != nil {
+		fn(end.crossCheckY)
+	}
+	if end := t.runEnd((*Tile).Down); end != nil {
+		fn(end.crossCheckY)
+	}
+	if end := t.runEnd((*Tile).Left); end != nil {
+		fn(end.crossCheckX)
+	}
+	if end := t.runEnd((*Tile).Right); end != nil {
+		fn(end.crossCheckX)
+	}
+}
+
+// runEnd returns the first empty tile past the letters next to t in the
+// direction of nextFn, or nil if the letters reach the edge of the board.
+func (t *Tile) runEnd(nextFn func(*Tile) *Tile) *Tile {
+	cur := nextFn(t)
+	for cur != nil && !cur.Empty() {
+		cur = nextFn(cur)
+	}
+	return cur
+}
+
+// key returns the Zobrist key of the letter on t.
+func (t *Tile) key() uint64 {
+	return zobristKey(t.orig, *t.letter)
+}
+
+func zobristKey(i int, l Letter) uint64 {
+	if l == 0 {
+		return 0
+	}
+	return zobrist.Square(i, l)
+}
+
 func (t *Tile) Row() int {
 	return t.i / t.board.size
 }
@@ -136,6 +210,16 @@ func (t *Tile) GatherRight() Word {
 	return t.gather((*Tile).Right)
 }
 
+// gatherPoints returns what the letters next to t in the direction of nextFn
+// are worth.
+func (t *Tile) gatherPoints(nextFn func(*Tile) *Tile) int {
+	var sum int
+	for cur := nextFn(t); !cur.Empty(); cur = nextFn(cur) {
+		sum += cur.Points()
+	}
+	return sum
+}
+
 func (t *Tile) gather(nextFn func(*Tile) *Tile) Word {
 	var buf strings.Builder
 	for cur := nextFn(t); !cur.Empty(); cur = nextFn(cur) {
@@ -145,14 +229,34 @@ func (t *Tile) gather(nextFn func(*Tile) *Tile) Word {
 }
 
 func (t *Tile) InYCrossCheck(l Letter) bool {
+	return t.YCrossCheck().Has(l)
+}
+
+// YCrossCheck returns the letters that can be put on t without making an
+// invalid down word.
+func (t *Tile) YCrossCheck() LetterSet {
 	if *t.board.staleY {
 		panic("stale cross-check")
 	}
-	if *t.crossCheckY == nil {
-		return true
+	return t.crossCheckY.letters
+}
+
+func (t *Tile) setYCrossCheck(dict *Node) {
+	t.crossCheckY.stale = false
+	if !t.YAnchor() {
+		t.crossCheckY.letters = AllLetters
+		return
+	}
+	var set LetterSet
+	above := dict.Search(t.GatherUp())
+	below := t.GatherDown()
+	for ls := above.EdgeSet(); ls != 0; ls &= ls - 1 {
+		l := ls.First()
+		if above.Child(l).Search(below).Accept() {
+			set = set.With(l)
+		}
 	}
-	_, ok := (*t.crossCheckY)[l]
-	return ok
+	t.crossCheckY.letters = set
 }
 
 func (t *Tile) Premium() (factor int, word bool) {
@@ -0,0 +1,65 @@
+package board
+
+import (
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+	. "github.com/tmazeika/scrabble-go/internal/move"
+)
+
+// Undo records what PlaceMove changed so that UndoMove can restore it.
+type Undo struct {
+	letters []*Letter
+	checks  []savedCrossCheck
+	staleX  bool
+	staleY  bool
+	hash    uint64
+}
+
+type savedCrossCheck struct {
+	check *crossCheck
+	saved crossCheck
+}
+
+// PlaceMove puts the tiles of m on the board. Apart from needing to fit on
+// the board, m is not validated.
+func (b *Board) PlaceMove(m Move) Undo {
+	u := Undo{
+		staleX: *b.staleX,
+		staleY: *b.staleY,
+		hash:   *b.hash,
+	}
+	if m.Skip || len(m.Word) == 0 && len(m.Tiles) == 0 {
+		return u
+	}
+	m, err := b.Complete(m)
+	if err != nil {
+		panic(err)
+	}
+	for _, p := range m.Tiles {
+		t := b.At(p.Row, p.Col)
+		t.dependents(func(c *crossCheck) {
+			u.checks = append(u.checks, savedCrossCheck{c, *c})
+		})
+		u.letters = append(u.letters, t.letter)
+		if p.Blank {
+			t.Set(p.Letter.Lower())
+		} else {
+			t.Set(p.Letter)
+		}
+	}
+	return u
+}
+
+// UndoMove takes back the move that returned u, restoring both the letters
+// and the cross-checks. Moves must be undone in the reverse order that they
+// were placed.
+func (b *Board) UndoMove(u Undo) {
+	for _, l := range u.letters {
+		*l = 0
+	}
+	for i := len(u.checks) - 1; i >= 0; i-- {
+		*u.checks[i].check = u.checks[i].saved
+	}
+	*b.staleX = u.staleX
+	*b.staleY = u.staleY
+	*b.hash = u.hash
+}
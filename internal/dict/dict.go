@@ -13,8 +13,35 @@ const Dict = "dictionary.txt"
 
 type Letter rune
 
+// Blank is the letter of a blank tile.
+const Blank Letter = '_'
+
+// Lower returns the lowercase form of l, which is how a blank standing for l
+// is written.
+func (l Letter) Lower() Letter {
+	if 'A' <= l && l <= 'Z' {
+		return l + 'a' - 'A'
+	}
+	return l
+}
+
+// Upper returns the uppercase form of l, which is the letter that a blank
+// written as l stands for.
+func (l Letter) Upper() Letter {
+	if 'a' <= l && l <= 'z' {
+		return l - 'a' + 'A'
+	}
+	return l
+}
+
+// IsLower reports whether l is lowercase, that is, a blank standing for a
+// letter.
+func (l Letter) IsLower() bool {
+	return 'a' <= l && l <= 'z'
+}
+
 func IsLetter(r rune) bool {
-	return 'A' <= r && r <= 'Z' || r == '_'
+	return 'A' <= r && r <= 'Z' || r == rune(Blank)
 }
 
 func Contains(letters []Letter, letter Letter) bool {
@@ -57,6 +84,11 @@ func (w Word) Reverse() Word {
 	return Word(rs)
 }
 
+// Upper returns w with every letter in uppercase.
+func (w Word) Upper() Word {
+	return Word(strings.ToUpper(string(w)))
+}
+
 func (w Word) Append(l Letter) Word {
 	return Word(string(w) + string(l))
 }
@@ -146,14 +178,48 @@ func Load(filename string) (n *Node, err error) {
 	scanner := bufio.NewScanner(f)
 	n = NewNode()
 	for scanner.Scan() {
-		line := scanner.Bytes()
-		if len(line) > 0 {
-			n.Insert(Word(line))
+		word, def := splitLine(scanner.Text())
+		if len(word) == 0 {
+			continue
+		}
+		n.Insert(word)
+		if def != "" {
+			n.Search(word).ensureMeta().Definition = def
 		}
 	}
 	return n, scanner.Err()
 }
 
+// LoadDefinitions reads a file of "WORD definition" lines and attaches each
+// definition to the matching word in n. Words that n does not accept are
+// skipped.
+func LoadDefinitions(n *Node, filename string) (err error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer safeClose(f, &err)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		word, def := splitLine(scanner.Text())
+		if end := n.Search(word); end.Accept() && def != "" {
+			end.ensureMeta().Definition = def
+		}
+	}
+	return scanner.Err()
+}
+
+// splitLine splits a lexicon line into its word and the optional definition
+// that follows it.
+func splitLine(line string) (Word, string) {
+	line = strings.TrimSpace(line)
+	i := strings.IndexAny(line, " \t")
+	if i == -1 {
+		return Word(strings.ToUpper(line)), ""
+	}
+	return Word(strings.ToUpper(line[:i])), strings.TrimSpace(line[i+1:])
+}
+
 func safeClose(closer io.Closer, err *error) {
 	if cerr := closer.Close(); cerr != nil && *err == nil {
 		*err = cerr
@@ -0,0 +1,107 @@
+package dict
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// LetterSet is a set of letters of the alphabet, one bit per letter. It can
+// hold alphabets of up to 64 tiles.
+type LetterSet uint64
+
+// AllLetters is the set of every letter.
+const AllLetters = ^LetterSet(0)
+
+// alphabet holds every letter in the tile distribution, in order, and
+// letterIndex maps each letter back to its position in alphabet.
+var (
+	alphabet    []Letter
+	letterIndex [256]int8
+)
+
+func init() {
+	for l := range letterProps {
+		alphabet = append(alphabet, l)
+	}
+	sort.Slice(alphabet, func(i, j int) bool {
+		return alphabet[i] < alphabet[j]
+	})
+	if len(alphabet) > 64 {
+		panic(fmt.Sprintf("alphabet of %d letters is too large",
+			len(alphabet)))
+	}
+	for i := range letterIndex {
+		letterIndex[i] = -1
+	}
+	for i, l := range alphabet {
+		if l >= 256 {
+			panic(fmt.Sprintf("letter %q is out of range", l))
+		}
+		letterIndex[l] = int8(i)
+	}
+}
+
+// Index returns the position of l in the alphabet, or -1 if l is not part of
+// it.
+func (l Letter) Index() int {
+	if l < 0 || l >= 256 {
+		return -1
+	}
+	return int(letterIndex[l])
+}
+
+// LetterSetOf returns the set of the given letters.
+func LetterSetOf(ls ...Letter) LetterSet {
+	var s LetterSet
+	for _, l := range ls {
+		s = s.With(l)
+	}
+	return s
+}
+
+func (s LetterSet) Has(l Letter) bool {
+	i := l.Index()
+	return i >= 0 && s&(1<<uint(i)) != 0
+}
+
+func (s LetterSet) With(l Letter) LetterSet {
+	i := l.Index()
+	if i < 0 {
+		panic(fmt.Sprintf("unknown letter %q", l))
+	}
+	return s | 1<<uint(i)
+}
+
+func (s LetterSet) Without(l Letter) LetterSet {
+	i := l.Index()
+	if i < 0 {
+		return s
+	}
+	return s &^ (1 << uint(i))
+}
+
+func (s LetterSet) Len() int {
+	return bits.OnesCount64(uint64(s))
+}
+
+// First returns the letter of s earliest in the alphabet. It must not be
+// called on an empty set.
+func (s LetterSet) First() Letter {
+	return alphabet[bits.TrailingZeros64(uint64(s))]
+}
+
+// Letters returns the letters of s in alphabetical order.
+func (s LetterSet) Letters() []Letter {
+	ls := make([]Letter, 0, s.Len())
+	for ; s != 0; s &= s - 1 {
+		ls = append(ls, s.First())
+	}
+	return ls
+}
+
+// rank returns the number of letters in s that come before l in the
+// alphabet.
+func (s LetterSet) rank(l Letter) int {
+	return bits.OnesCount64(uint64(s) & (1<<uint(l.Index()) - 1))
+}
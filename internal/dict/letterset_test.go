@@ -0,0 +1,29 @@
+package dict
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestLetterSet(t *testing.T) {
+	s := LetterSetOf('Q', 'A', 'Z', Blank)
+	assert.Equal(t, 4, s.Len())
+	assert.True(t, s.Has('A'))
+	assert.True(t, s.Has(Blank))
+	assert.False(t, s.Has('B'))
+	assert.False(t, s.Has('?'))
+	assert.Equal(t, Letter('A'), s.First())
+	assert.Equal(t, []Letter{'A', 'Q', 'Z', Blank}, s.Letters())
+	assert.Equal(t, []Letter{'A', 'Z', Blank}, s.Without('Q').Letters())
+	assert.True(t, AllLetters.Has('E'))
+	assert.Empty(t, LetterSet(0).Letters())
+}
+
+func TestNode_Child(t *testing.T) {
+	n := queryDict()
+	assert.Equal(t, LetterSetOf('A', 'C', 'E', 'S', 'T'), n.EdgeSet())
+	assert.True(t, n.Child('A').Child('T').Accept())
+	assert.Nil(t, n.Child('B'))
+	assert.Nil(t, n.Child('?'))
+	assert.Nil(t, n.Child('A').Child('Z').Child('Z'))
+}
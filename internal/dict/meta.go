@@ -0,0 +1,147 @@
+package dict
+
+import (
+	"sort"
+)
+
+// Flags is a set of caller-defined markers on a word, such as the lexicons it
+// does or does not belong to.
+type Flags uint32
+
+// Meta is the optional information attached to a word's accepting node.
+type Meta struct {
+	Definition string
+	Flags      Flags
+	// Probability is the number of ways the word can be drawn from the tile
+	// distribution it was ranked against, and Rank is its 1-based position
+	// among words of the same length ordered by that probability. Both are
+	// zero until RankProbabilities is called.
+	Probability float64
+	Rank        int
+}
+
+// Meta returns the metadata attached to n, or nil if there is none.
+func (n *Node) Meta() *Meta {
+	if n == nil {
+		return nil
+	}
+	return n.meta
+}
+
+func (n *Node) ensureMeta() *Meta {
+	if n.meta == nil {
+		n.meta = &Meta{}
+	}
+	return n.meta
+}
+
+// Lookup reports whether word is accepted by n, along with its metadata.
+func (n *Node) Lookup(word Word) (*Meta, bool) {
+	end := n.Search(word)
+	if !end.Accept() {
+		return nil, false
+	}
+	return end.meta, true
+}
+
+// FlagMissing adds f to the flags of every word accepted by n but not by
+// other, and returns how many words were flagged. For example, flagging a
+// CSW lexicon against a TWL lexicon marks the CSW-only words.
+func (n *Node) FlagMissing(other *Node, f Flags) int {
+	var count int
+	n.Walk(func(word Word, end *Node) bool {
+		if !other.Search(word).Accept() {
+			end.ensureMeta().Flags |= f
+			count++
+		}
+		return true
+	})
+	return count
+}
+
+// TileCounts is the number of tiles of each letter in a distribution.
+type TileCounts map[Letter]int
+
+// CountTiles counts the tiles in dist.
+func CountTiles(dist []Letter) TileCounts {
+	c := make(TileCounts)
+	for _, l := range dist {
+		c[l]++
+	}
+	return c
+}
+
+// Combinations returns the number of ways word can be drawn from the tiles in
+// c. A blank can stand in for any letter, whether or not that letter is short.
+func (c TileCounts) Combinations(word Word) float64 {
+	need := make(map[Letter]int)
+	for _, r := range word {
+		need[Letter(r)]++
+	}
+	blanks := c[Blank]
+	// ways[b] is the number of ways to draw the letters seen so far using b
+	// blanks in place of real tiles.
+	ways := make([]float64, blanks+1)
+	ways[0] = 1
+	for l, k := range need {
+		next := make([]float64, blanks+1)
+		for b, w := range ways {
+			if w == 0 {
+				continue
+			}
+			for real := 0; real <= k; real++ {
+				if b+k-real <= blanks {
+					next[b+k-real] += w * choose(c[l], real)
+				}
+			}
+		}
+		ways = next
+	}
+	combos := 0.0
+	for b, w := range ways {
+		combos += w * choose(blanks, b)
+	}
+	return combos
+}
+
+// Combinations returns the number of ways word can be drawn from the tiles in
+// dist. Callers drawing many words from the same tiles should count them once
+// with CountTiles instead.
+func Combinations(word Word, dist []Letter) float64 {
+	return CountTiles(dist).Combinations(word)
+}
+
+// RankProbabilities computes the probability of every word accepted by n
+// against the tiles in dist and ranks each word among the words of the same
+// length, most probable first, breaking ties alphabetically.
+func (n *Node) RankProbabilities(dist []Letter) {
+	counts := CountTiles(dist)
+	byLen := make(map[int][]*Meta)
+	n.Walk(func(word Word, end *Node) bool {
+		m := end.ensureMeta()
+		m.Probability = counts.Combinations(word)
+		byLen[len(word)] = append(byLen[len(word)], m)
+		return true
+	})
+	for _, ms := range byLen {
+		// Walk visits words alphabetically, so a stable sort keeps ties in
+		// alphabetical order.
+		sort.SliceStable(ms, func(i, j int) bool {
+			return ms[i].Probability > ms[j].Probability
+		})
+		for i, m := range ms {
+			m.Rank = i + 1
+		}
+	}
+}
+
+func choose(n, k int) float64 {
+	if k < 0 || k > n {
+		return 0
+	}
+	c := 1.0
+	for i := 0; i < k; i++ {
+		c = c * float64(n-i) / float64(i+1)
+	}
+	return c
+}
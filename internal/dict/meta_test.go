@@ -0,0 +1,78 @@
+package dict
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad_Definitions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dict")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	lexicon := filepath.Join(dir, "lexicon.txt")
+	defs := filepath.Join(dir, "defs.txt")
+	assert.Nil(t, ioutil.WriteFile(lexicon,
+		[]byte("QI a life force\nZA\nXU\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(defs,
+		[]byte("ZA pizza\nZZZ sleep\n"), 0644))
+
+	n, err := Load(lexicon)
+	assert.Nil(t, err)
+	assert.Nil(t, LoadDefinitions(n, defs))
+	m, ok := n.Lookup("QI")
+	assert.True(t, ok)
+	assert.Equal(t, "a life force", m.Definition)
+	m, ok = n.Lookup("ZA")
+	assert.True(t, ok)
+	assert.Equal(t, "pizza", m.Definition)
+	m, ok = n.Lookup("XU")
+	assert.True(t, ok)
+	assert.Nil(t, m)
+	_, ok = n.Lookup("ZZZ")
+	assert.False(t, ok)
+}
+
+func TestNode_FlagMissing(t *testing.T) {
+	csw := queryDict()
+	twl := NewNode()
+	twl.Insert("EAT")
+	twl.Insert("TEA")
+	const cswOnly Flags = 1
+	assert.Equal(t, 13, csw.FlagMissing(twl, cswOnly))
+	m, _ := csw.Lookup("EAT")
+	assert.Nil(t, m)
+	m, _ = csw.Lookup("ETA")
+	assert.Equal(t, cswOnly, m.Flags)
+}
+
+func TestCombinations(t *testing.T) {
+	dist := []Letter("AAAEEBB_")
+	// AE, A?, ?E, ...
+	assert.Equal(t, 3.0*2.0+3.0+2.0, Combinations("AE", dist))
+	assert.Equal(t, 3.0+3.0, Combinations("AA", dist))
+	assert.Equal(t, 1.0, Combinations("AAAA", dist))
+	assert.Equal(t, 0.0, Combinations("AAAAA", dist))
+	assert.Equal(t, 0.0, Combinations("X", []Letter("AB")))
+	assert.Equal(t, 1.0, Combinations("X", []Letter("AB_")))
+	assert.Equal(t, 1.0+2.0, CountTiles(dist).Combinations("BB"))
+}
+
+func TestNode_RankProbabilities(t *testing.T) {
+	n := NewNode()
+	for _, w := range []Word{"ZA", "AA", "AE", "QI", "EAT"} {
+		n.Insert(w)
+	}
+	n.RankProbabilities(LettersDist())
+	rank := func(w Word) int {
+		m, _ := n.Lookup(w)
+		return m.Rank
+	}
+	assert.Equal(t, 1, rank("AE"))
+	assert.Equal(t, 2, rank("AA"))
+	assert.Equal(t, 3, rank("QI"))
+	assert.Equal(t, 4, rank("ZA"))
+	assert.Equal(t, 1, rank("EAT"))
+}
@@ -0,0 +1,118 @@
+package dict
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+)
+
+// Overlay returns a lexicon that accepts the words of base plus the words in
+// add, minus the words in remove. Removals win over additions. Only the nodes
+// on the paths of changed words are copied; the rest are shared with base, so
+// base must not be modified afterwards.
+func Overlay(base *Node, add, remove []Word) *Node {
+	n := base
+	for _, w := range add {
+		n = n.with(w, true)
+	}
+	for _, w := range remove {
+		n = n.with(w, false)
+	}
+	return n
+}
+
+// LoadOverlay reads an add-list and a remove-list, one word per line, and
+// layers them over base. Either filename may be empty.
+func LoadOverlay(base *Node, addFile, removeFile string) (*Node, error) {
+	var add, remove []Word
+	var err error
+	if addFile != "" {
+		if add, err = readWords(addFile); err != nil {
+			return nil, err
+		}
+	}
+	if removeFile != "" {
+		if remove, err = readWords(removeFile); err != nil {
+			return nil, err
+		}
+	}
+	return Overlay(base, add, remove), nil
+}
+
+// with returns a copy of n in which word is accepted or not. Nodes that are
+// left with no words under them are pruned.
+func (n *Node) with(word Word, accept bool) *Node {
+	n2 := n.shallowCopy()
+	if len(word) == 0 {
+		n2.accept = accept
+		return n2
+	}
+	next := n.Child(word.Head())
+	if next == nil {
+		if !accept {
+			return n
+		}
+		next = NewNode()
+	}
+	next = next.with(word.Tail(), accept)
+	if next.Accept() || next.edges != 0 {
+		n2.setChild(word.Head(), next)
+	} else {
+		n2.removeChild(word.Head())
+	}
+	return n2
+}
+
+func (n *Node) shallowCopy() *Node {
+	n2 := NewNode()
+	if n == nil {
+		return n2
+	}
+	n2.edges = n.edges
+	n2.children = append([]*Node(nil), n.children...)
+	n2.accept = n.accept
+	n2.meta = n.meta
+	return n2
+}
+
+// Diff returns the words accepted by b but not a, and the words accepted by a
+// but not b, both in alphabetical order.
+func Diff(a, b *Node) (added, removed []Word) {
+	b.Walk(func(word Word, _ *Node) bool {
+		if !a.Search(word).Accept() {
+			added = append(added, word)
+		}
+		return true
+	})
+	a.Walk(func(word Word, _ *Node) bool {
+		if !b.Search(word).Accept() {
+			removed = append(removed, word)
+		}
+		return true
+	})
+	return added, removed
+}
+
+// readWords reads the first word of each line of filename. Words must be
+// made of the letters A to Z.
+func readWords(filename string) (ws []Word, err error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer safeClose(f, &err)
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		word, _ := splitLine(scanner.Text())
+		for _, r := range word {
+			if Letter(r).Index() < 0 {
+				return nil, fmt.Errorf("%s:%d: %q is not a letter in %s",
+					filename, line, r, word)
+			}
+		}
+		if len(word) > 0 {
+			ws = append(ws, word)
+		}
+	}
+	return ws, scanner.Err()
+}
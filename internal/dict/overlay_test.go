@@ -0,0 +1,54 @@
+package dict
+
+import (
+	"github.com/stretchr/testify/assert"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOverlay(t *testing.T) {
+	base := queryDict()
+	n := Overlay(base, []Word{"TEXAS", "EA"}, []Word{"EAT", "SCAT", "ZZZ"})
+	assert.True(t, n.Search("TEXAS").Accept())
+	assert.True(t, n.Search("EA").Accept())
+	assert.False(t, n.Search("EAT").Accept())
+	assert.True(t, n.Search("EATS").Accept())
+	assert.False(t, n.Search("SCAT").Accept())
+	assert.Nil(t, n.Search("SC"))
+	assert.False(t, n.Search("ZZZ").Accept())
+
+	// The base lexicon is left untouched.
+	assert.False(t, base.Search("TEXAS").Accept())
+	assert.True(t, base.Search("EAT").Accept())
+	assert.True(t, base.Search("SCAT").Accept())
+}
+
+func TestDiff(t *testing.T) {
+	base := queryDict()
+	n := Overlay(base, []Word{"TEXAS", "EA"}, []Word{"EAT", "SCAT"})
+	added, removed := Diff(base, n)
+	assert.Equal(t, []Word{"EA", "TEXAS"}, added)
+	assert.Equal(t, []Word{"EAT", "SCAT"}, removed)
+	added, removed = Diff(n, n)
+	assert.Empty(t, added)
+	assert.Empty(t, removed)
+}
+
+func TestLoadOverlay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dict")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+	add := filepath.Join(dir, "add.txt")
+	remove := filepath.Join(dir, "remove.txt")
+	assert.Nil(t, ioutil.WriteFile(add, []byte("texas\nEA a river\n"), 0644))
+	assert.Nil(t, ioutil.WriteFile(remove, []byte("EAT\n\nDON'T\n"), 0644))
+
+	n, err := LoadOverlay(queryDict(), add, "")
+	assert.Nil(t, err)
+	assert.True(t, n.Search("TEXAS").Accept())
+	assert.True(t, n.Search("EA").Accept())
+	_, err = LoadOverlay(queryDict(), add, remove)
+	assert.EqualError(t, err, remove+`:3: '\'' is not a letter in DON'T`)
+}
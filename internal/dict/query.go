@@ -0,0 +1,212 @@
+package dict
+
+import (
+	"sort"
+	"strings"
+)
+
+// Query metacharacters. Either blank character stands for exactly one letter
+// in a rack or a pattern, and Star stands for any run of letters (including an
+// empty one) in a pattern.
+const (
+	Wildcard = '?'
+	Star     = '*'
+)
+
+func isBlank(r rune) bool {
+	return r == Wildcard || r == rune(Blank)
+}
+
+// rackCounts holds the letters of a query rack and how many blanks it has.
+type rackCounts struct {
+	letters map[Letter]int
+	blanks  int
+}
+
+func newRackCounts(rack string) rackCounts {
+	rc := rackCounts{letters: make(map[Letter]int)}
+	for _, r := range strings.ToUpper(rack) {
+		if isBlank(r) {
+			rc.blanks++
+		} else {
+			rc.letters[Letter(r)]++
+		}
+	}
+	return rc
+}
+
+// take removes l from the rack, using up a blank if l itself is not there.
+// It returns a function that puts the letter back.
+func (rc *rackCounts) take(l Letter) (undo func(), ok bool) {
+	if rc.letters[l] > 0 {
+		rc.letters[l]--
+		return func() { rc.letters[l]++ }, true
+	}
+	if rc.blanks > 0 {
+		rc.blanks--
+		return func() { rc.blanks++ }, true
+	}
+	return nil, false
+}
+
+// results collects words up to a limit. A limit <= 0 means no limit.
+type results struct {
+	words []Word
+	limit int
+}
+
+func (rs *results) add(w Word) bool {
+	rs.words = append(rs.words, w)
+	return !rs.full()
+}
+
+func (rs *results) full() bool {
+	return rs.limit > 0 && len(rs.words) >= rs.limit
+}
+
+// Anagrams returns the words that use every letter of rack, in alphabetical
+// order. Blanks ('?' or '_') in rack may stand for any letter.
+func (n *Node) Anagrams(rack string, limit int) []Word {
+	rc := newRackCounts(rack)
+	rs := results{limit: limit}
+	n.anagrams(&rc, "", len([]rune(rack)), &rs)
+	return rs.words
+}
+
+// Subanagrams returns the words of at least minLen letters that can be made
+// from some of the letters of rack, ordered by length (longest first) and
+// then alphabetically.
+func (n *Node) Subanagrams(rack string, minLen, limit int) []Word {
+	rc := newRackCounts(rack)
+	rs := results{}
+	n.anagrams(&rc, "", -1, &rs)
+	var words []Word
+	for _, w := range rs.words {
+		if len(w) >= minLen {
+			words = append(words, w)
+		}
+	}
+	sort.SliceStable(words, func(i, j int) bool {
+		return len(words[i]) > len(words[j])
+	})
+	if limit > 0 && len(words) > limit {
+		words = words[:limit]
+	}
+	return words
+}
+
+// anagrams walks n collecting words spelled from rc. If length is negative,
+// words of any length are collected, otherwise only words of exactly that
+// many letters.
+func (n *Node) anagrams(rc *rackCounts, prefix Word, length int,
+	rs *results) bool {
+	if n.Accept() && len(prefix) > 0 && (length < 0 || len(prefix) == length) {
+		if !rs.add(prefix) {
+			return false
+		}
+	}
+	if length >= 0 && len(prefix) >= length {
+		return true
+	}
+	for _, l := range n.EdgeSet().Letters() {
+		undo, ok := rc.take(l)
+		if !ok {
+			continue
+		}
+		cont := n.Child(l).anagrams(rc, prefix.Append(l), length, rs)
+		undo()
+		if !cont {
+			return false
+		}
+	}
+	return true
+}
+
+// Pattern returns the words matching pattern, in alphabetical order. A
+// Wildcard ('?' or '_') matches exactly one letter and a Star matches any
+// number of letters. With a limit, the first limit of them are returned.
+func (n *Node) Pattern(pattern string, limit int) []Word {
+	// A star can reach a word after one that comes later alphabetically, so
+	// every match is found before cutting to the limit.
+	rs := results{}
+	n.pattern([]rune(strings.ToUpper(pattern)), "", &rs,
+		make(map[patternKey]bool))
+	sort.Slice(rs.words, func(i, j int) bool {
+		return rs.words[i] < rs.words[j]
+	})
+	if limit > 0 && len(rs.words) > limit {
+		rs.words = rs.words[:limit]
+	}
+	return rs.words
+}
+
+// patternKey identifies a (node, remaining pattern) state so that patterns
+// with several stars do not report the same word twice.
+type patternKey struct {
+	node    *Node
+	prefix  Word
+	pattern int
+}
+
+func (n *Node) pattern(pattern []rune, prefix Word, rs *results,
+	seen map[patternKey]bool) bool {
+	key := patternKey{n, prefix, len(pattern)}
+	if seen[key] {
+		return true
+	}
+	seen[key] = true
+	if len(pattern) == 0 {
+		if n.Accept() && len(prefix) > 0 {
+			return rs.add(prefix)
+		}
+		return true
+	}
+	head, rest := pattern[0], pattern[1:]
+	switch {
+	case head == Star:
+		if !n.pattern(rest, prefix, rs, seen) {
+			return false
+		}
+		for _, l := range n.EdgeSet().Letters() {
+			if !n.Child(l).pattern(pattern, prefix.Append(l), rs, seen) {
+				return false
+			}
+		}
+	case isBlank(head):
+		for _, l := range n.EdgeSet().Letters() {
+			if !n.Child(l).pattern(rest, prefix.Append(l), rs, seen) {
+				return false
+			}
+		}
+	default:
+		if next := n.Child(Letter(head)); next != nil {
+			return next.pattern(rest, prefix.Append(Letter(head)), rs, seen)
+		}
+	}
+	return true
+}
+
+// FrontHooks returns the letters that can be put in front of word to make
+// another word.
+func (n *Node) FrontHooks(word Word) []Letter {
+	var hooks []Letter
+	for _, l := range n.EdgeSet().Letters() {
+		if n.Child(l).Search(word).Accept() {
+			hooks = append(hooks, l)
+		}
+	}
+	return hooks
+}
+
+// BackHooks returns the letters that can be put after word to make another
+// word.
+func (n *Node) BackHooks(word Word) []Letter {
+	var hooks []Letter
+	end := n.Search(word)
+	for _, l := range end.EdgeSet().Letters() {
+		if end.Child(l).Accept() {
+			hooks = append(hooks, l)
+		}
+	}
+	return hooks
+}
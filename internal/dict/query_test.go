@@ -0,0 +1,53 @@
+package dict
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func queryDict() *Node {
+	n := NewNode()
+	for _, w := range []Word{"AT", "TA", "EAT", "TEA", "ATE", "ETA", "EATS",
+		"SEAT", "TEAS", "EAST", "SATE", "ETAS", "CAT", "CATS", "SCAT"} {
+		n.Insert(w)
+	}
+	return n
+}
+
+func TestNode_Anagrams(t *testing.T) {
+	n := queryDict()
+	assert.Equal(t, []Word{"ATE", "EAT", "ETA", "TEA"}, n.Anagrams("TEA", 0))
+	assert.Equal(t, []Word{"ATE", "EAT"}, n.Anagrams("TEA", 2))
+	assert.Equal(t, []Word{"ATE", "CAT", "EAT", "ETA", "TEA"},
+		n.Anagrams("T?A", 0))
+	assert.Empty(t, n.Anagrams("XYZ", 0))
+}
+
+func TestNode_Subanagrams(t *testing.T) {
+	n := queryDict()
+	assert.Equal(t, []Word{"CATS", "SCAT", "CAT", "AT", "TA"},
+		n.Subanagrams("SCAT", 2, 0))
+	assert.Equal(t, []Word{"CATS", "SCAT", "CAT"},
+		n.Subanagrams("SCAT", 3, 0))
+	assert.Equal(t, []Word{"CATS"}, n.Subanagrams("SCAT", 2, 1))
+}
+
+func TestNode_Pattern(t *testing.T) {
+	n := queryDict()
+	assert.Equal(t, []Word{"EATS", "ETAS"}, n.Pattern("E??S", 0))
+	assert.Equal(t, []Word{"CATS", "EATS", "ETAS", "TEAS"}, n.Pattern("*S", 0))
+	assert.Equal(t, []Word{"SCAT", "SEAT"}, n.Pattern("S*T", 0))
+	assert.Equal(t, []Word{"AT", "CAT", "EAST", "EAT", "SCAT", "SEAT"},
+		n.Pattern("*A*T", 0))
+	assert.Len(t, n.Pattern("*", 3), 3)
+	// EAT is found before EAST, which comes first.
+	assert.Equal(t, []Word{"EAST"}, n.Pattern("EA*T", 1))
+}
+
+func TestNode_Hooks(t *testing.T) {
+	n := queryDict()
+	assert.Equal(t, []Letter{'C', 'E'}, n.FrontHooks("AT"))
+	assert.Equal(t, []Letter{'E'}, n.BackHooks("AT"))
+	assert.Equal(t, []Letter{'S'}, n.BackHooks("EAT"))
+	assert.Empty(t, n.BackHooks("XYZ"))
+}
@@ -1,46 +1,97 @@
 package dict
 
+// Node is a node of a lexicon trie. Its edges are kept as a letter set along
+// with the child nodes in alphabetical order, so the child for a letter is at
+// the letter's rank in the set.
 type Node struct {
-	edges  map[Letter]*Node
-	accept bool
+	edges    LetterSet
+	children []*Node
+	accept   bool
+	meta     *Meta
 }
 
 func NewNode() *Node {
-	return &Node{
-		edges: make(map[Letter]*Node),
-	}
+	return &Node{}
 }
 
-func (n *Node) Edges() map[Letter]*Node {
+// EdgeSet returns the letters that n has edges for.
+func (n *Node) EdgeSet() LetterSet {
 	if n == nil {
-		return nil
+		return 0
 	}
 	return n.edges
 }
 
+// Child returns the node at the end of the edge for l, or nil if there is no
+// such edge.
+func (n *Node) Child(l Letter) *Node {
+	if !n.EdgeSet().Has(l) {
+		return nil
+	}
+	return n.children[n.edges.rank(l)]
+}
+
 func (n *Node) Accept() bool {
 	return n != nil && n.accept
 }
 
 func (n *Node) Search(word Word) *Node {
-	if len(word) == 0 {
+	if len(word) == 0 || n == nil {
 		return n
 	}
-	next, ok := n.edges[word.Head()]
-	if !ok {
-		return nil
-	}
-	return next.Search(word.Tail())
+	return n.Child(word.Head()).Search(word.Tail())
 }
 
 func (n *Node) Insert(word Word) {
 	if len(word) == 0 {
 		n.accept = true
-	} else if next, ok := n.edges[word.Head()]; ok {
-		next.Insert(word.Tail())
-	} else {
-		next := NewNode()
-		n.edges[word.Head()] = next
-		next.Insert(word.Tail())
+		return
+	}
+	next := n.Child(word.Head())
+	if next == nil {
+		next = NewNode()
+		n.setChild(word.Head(), next)
+	}
+	next.Insert(word.Tail())
+}
+
+// setChild points the edge for l at next, adding the edge if needed.
+func (n *Node) setChild(l Letter, next *Node) {
+	i := n.edges.rank(l)
+	if n.edges.Has(l) {
+		n.children[i] = next
+		return
+	}
+	n.edges = n.edges.With(l)
+	n.children = append(n.children, nil)
+	copy(n.children[i+1:], n.children[i:])
+	n.children[i] = next
+}
+
+// removeChild removes the edge for l, if any.
+func (n *Node) removeChild(l Letter) {
+	if !n.edges.Has(l) {
+		return
+	}
+	i := n.edges.rank(l)
+	n.children = append(n.children[:i], n.children[i+1:]...)
+	n.edges = n.edges.Without(l)
+}
+
+// Walk calls fn for every word accepted by n, in alphabetical order, along
+// with the node that accepts it. Walking stops early if fn returns false.
+func (n *Node) Walk(fn func(word Word, node *Node) bool) {
+	n.walk("", fn)
+}
+
+func (n *Node) walk(prefix Word, fn func(Word, *Node) bool) bool {
+	if n.Accept() && !fn(prefix, n) {
+		return false
+	}
+	for _, l := range n.EdgeSet().Letters() {
+		if !n.Child(l).walk(prefix.Append(l), fn) {
+			return false
+		}
 	}
+	return true
 }
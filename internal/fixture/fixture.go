@@ -0,0 +1,29 @@
+// Package fixture reads the test fixtures kept in testdata directories.
+package fixture
+
+import (
+	"io/ioutil"
+	"strings"
+)
+
+// Read splits the file at path into its paragraphs, leaving out comment
+// lines starting with '#'.
+func Read(path string) ([]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(data), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	var paragraphs []string
+	for _, p := range strings.Split(strings.Join(lines, "\n"), "\n\n") {
+		if p = strings.Trim(p, "\n"); p != "" {
+			paragraphs = append(paragraphs, p)
+		}
+	}
+	return paragraphs, nil
+}
@@ -0,0 +1,273 @@
+// Package leaves values the tiles kept on a rack after a move.
+package leaves
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/tmazeika/scrabble-go/internal/dict"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Table gives the value, in points, of keeping a leave. Leaves it has no
+// value for are valued by adding up their parts: each letter on its own,
+// a penalty for each duplicate letter, the balance of vowels and consonants
+// and a Q without a U.
+type Table struct {
+	Singles map[dict.Letter]float64
+	// Duplicates is the penalty for each copy of a letter past the first.
+	// Letters it leaves out get DefaultDuplicate.
+	Duplicates       map[dict.Letter]float64
+	DefaultDuplicate float64
+	// Balance is keyed by the number of vowels minus the number of
+	// consonants, clamped to MaxImbalance either way.
+	Balance   map[int]float64
+	QWithoutU float64
+
+	leaves map[string]float64
+}
+
+// MaxImbalance is the largest difference between vowels and consonants that
+// the Balance of a Table tells apart.
+const MaxImbalance = 4
+
+// Heuristic returns a Table with no leave values, only the parts to value
+// them by.
+func Heuristic() *Table {
+	return &Table{
+		Singles: map[dict.Letter]float64{
+			'A': 1, 'B': -2, 'C': 0.5, 'D': 0.5, 'E': 1.5, 'F': -2,
+			'G': -2.5, 'H': 1, 'I': -0.5, 'J': -3, 'K': -2.5, 'L': -0.5,
+			'M': 0, 'N': 0.5, 'O': -1, 'P': -0.5, 'Q': -7, 'R': 1.5,
+			'S': 7.5, 'T': 0.5, 'U': -3, 'V': -5.5, 'W': -4, 'X': 3.5,
+			'Y': -0.5, 'Z': 2, dict.Blank: 25,
+		},
+		Duplicates: map[dict.Letter]float64{
+			'I': -4, 'U': -5, 'V': -4, 'W': -4, dict.Blank: -10,
+		},
+		DefaultDuplicate: -3,
+		Balance: map[int]float64{
+			-4: -6, -3: -3, -2: -1, -1: 0, 0: 0, 1: 0, 2: -2.5, 3: -6, 4: -10,
+		},
+		QWithoutU: -5,
+		leaves:    make(map[string]float64),
+	}
+}
+
+// Key returns the letters of leave in order, with '?' for a blank, which is
+// how leaves are written in a table file.
+func Key(leave []dict.Letter) string {
+	ls := make([]dict.Letter, len(leave))
+	copy(ls, leave)
+	sort.Slice(ls, func(i, j int) bool {
+		return ls[i] < ls[j]
+	})
+	var buf strings.Builder
+	for _, l := range ls {
+		if l == dict.Blank {
+			l = '?'
+		}
+		buf.WriteRune(rune(l))
+	}
+	return buf.String()
+}
+
+// Value returns what keeping leave is worth.
+func (t *Table) Value(leave []dict.Letter) float64 {
+	if len(leave) == 0 {
+		return 0
+	}
+	if v, ok := t.Lookup(leave); ok {
+		return v
+	}
+	return t.Estimate(leave)
+}
+
+// Lookup returns the value that t has for leave, if it has one.
+func (t *Table) Lookup(leave []dict.Letter) (float64, bool) {
+	v, ok := t.leaves[Key(leave)]
+	return v, ok
+}
+
+// Estimate values leave by its parts, even if t has a value for it.
+func (t *Table) Estimate(leave []dict.Letter) float64 {
+	var v float64
+	var vowels, consonants int
+	seen := make(map[dict.Letter]bool)
+	q, u := false, false
+	for _, l := range leave {
+		v += t.Singles[l]
+		if seen[l] {
+			if d, ok := t.Duplicates[l]; ok {
+				v += d
+			} else {
+				v += t.DefaultDuplicate
+			}
+		}
+		seen[l] = true
+		switch l {
+		case 'A', 'E', 'I', 'O', 'U':
+			vowels++
+		case dict.Blank:
+		default:
+			consonants++
+		}
+		q = q || l == 'Q'
+		u = u || l == 'U'
+	}
+	diff := vowels - consonants
+	if diff > MaxImbalance {
+		diff = MaxImbalance
+	} else if diff < -MaxImbalance {
+		diff = -MaxImbalance
+	}
+	v += t.Balance[diff]
+	if q && !u {
+		v += t.QWithoutU
+	}
+	return v
+}
+
+// Set makes v the value of leave.
+func (t *Table) Set(leave []dict.Letter, v float64) {
+	t.leaves[Key(leave)] = v
+}
+
+// Len returns how many leaves t has values for.
+func (t *Table) Len() int {
+	return len(t.leaves)
+}
+
+// Each calls fn with every leave of 1 to max tiles that can be drawn from
+// dist, a bag of tiles, with the letters of each in order. The slice passed
+// to fn is reused.
+func Each(dist []dict.Letter, max int, fn func(leave []dict.Letter)) {
+	counts := make(map[dict.Letter]int)
+	for _, l := range dist {
+		counts[l]++
+	}
+	letters := make([]dict.Letter, 0, len(counts))
+	for l := range counts {
+		letters = append(letters, l)
+	}
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i] < letters[j]
+	})
+	leave := make([]dict.Letter, 0, max)
+	var each func(i int)
+	each = func(i int) {
+		if len(leave) > 0 {
+			fn(leave)
+		}
+		if len(leave) == max {
+			return
+		}
+		for j := i; j < len(letters); j++ {
+			l := letters[j]
+			if count(leave, l) == counts[l] {
+				continue
+			}
+			leave = append(leave, l)
+			each(j)
+			leave = leave[:len(leave)-1]
+		}
+	}
+	each(0)
+}
+
+func count(letters []dict.Letter, l dict.Letter) int {
+	var n int
+	for _, l2 := range letters {
+		if l2 == l {
+			n++
+		}
+	}
+	return n
+}
+
+// Load reads a table file; see Read.
+func Load(filename string) (t *Table, err error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer safeClose(f, &err)
+	return Read(f)
+}
+
+func safeClose(closer io.Closer, err *error) {
+	if cerr := closer.Close(); cerr != nil && *err == nil {
+		*err = cerr
+	}
+}
+
+// Read reads a table from lines of a leave and its value, such as "ERS 5.2",
+// on top of Heuristic. A leave of one letter also sets that letter's Single,
+// so that the leaves valued by their parts agree with it. Blank lines and
+// lines starting with '#' are skipped.
+func Read(r io.Reader) (*Table, error) {
+	t := Heuristic()
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		s := strings.TrimSpace(scanner.Text())
+		if s == "" || strings.HasPrefix(s, "#") {
+			continue
+		}
+		fields := strings.Fields(s)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: want a leave and a value", line)
+		}
+		leave, err := parseLeave(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		v, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		t.Set(leave, v)
+		if len(leave) == 1 {
+			t.Singles[leave[0]] = v
+		}
+	}
+	return t, scanner.Err()
+}
+
+func parseLeave(s string) ([]dict.Letter, error) {
+	var leave []dict.Letter
+	for _, r := range strings.ToUpper(s) {
+		switch {
+		case r == '?' || r == rune(dict.Blank):
+			leave = append(leave, dict.Blank)
+		case 'A' <= r && r <= 'Z':
+			leave = append(leave, dict.Letter(r))
+		default:
+			return nil, fmt.Errorf("bad leave %q", s)
+		}
+	}
+	return leave, nil
+}
+
+// Write writes the leave values of t in the format Read reads, in order of
+// length and then letters.
+func (t *Table) Write(w io.Writer) error {
+	keys := make([]string, 0, len(t.leaves))
+	for k := range t.leaves {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if len(keys[i]) != len(keys[j]) {
+			return len(keys[i]) < len(keys[j])
+		}
+		return keys[i] < keys[j]
+	})
+	bw := bufio.NewWriter(w)
+	for _, k := range keys {
+		fmt.Fprintf(bw, "%s %s\n", k,
+			strconv.FormatFloat(t.leaves[k], 'f', -1, 64))
+	}
+	return bw.Flush()
+}
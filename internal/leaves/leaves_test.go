@@ -0,0 +1,75 @@
+package leaves
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"github.com/tmazeika/scrabble-go/internal/dict"
+	"strings"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, "ERS?", Key([]dict.Letter("S_RE")))
+	assert.Equal(t, "", Key(nil))
+}
+
+func TestTable_Estimate(t *testing.T) {
+	h := Heuristic()
+	assert.Equal(t, 0.0, h.Value(nil))
+	assert.Equal(t, 7.5, h.Value([]dict.Letter("S")))
+	// E and R, plus a vowel and a consonant.
+	assert.Equal(t, 3.0, h.Value([]dict.Letter("ER")))
+	// Two singles and the duplicate E, with two vowels.
+	assert.Equal(t, 1.5+1.5-3-2.5, h.Value([]dict.Letter("EE")))
+	// Q without U, and three consonants.
+	assert.Equal(t, -7-5.5-5.5-4-5-3, h.Value([]dict.Letter("QVV")))
+	assert.True(t, h.Value([]dict.Letter("QU")) > h.Value([]dict.Letter("Q")))
+	assert.True(t, h.Value([]dict.Letter("ERS")) >
+		h.Value([]dict.Letter("QVVU")))
+}
+
+func TestRead(t *testing.T) {
+	table, err := Read(strings.NewReader(`
+# A few leaves.
+ers 9.5
+S 8
+Q? 12
+`))
+	assert.Nil(t, err)
+	assert.Equal(t, 3, table.Len())
+	assert.Equal(t, 9.5, table.Value([]dict.Letter("SRE")))
+	assert.Equal(t, 12.0, table.Value([]dict.Letter("_Q")))
+	assert.Equal(t, 8.0, table.Singles['S'])
+	assert.Equal(t, table.Estimate([]dict.Letter("ES")),
+		table.Value([]dict.Letter("ES")))
+	assert.Equal(t, 8+1.5+0.0, table.Value([]dict.Letter("ES")))
+
+	var buf bytes.Buffer
+	assert.Nil(t, table.Write(&buf))
+	assert.Equal(t, "S 8\nQ? 12\nERS 9.5\n", buf.String())
+	table2, err := Read(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, table, table2)
+
+	for _, s := range []string{"ERS", "ERS x", "E1S 2", "ERS 2 3"} {
+		_, err := Read(strings.NewReader(s))
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestEach(t *testing.T) {
+	var keys []string
+	Each([]dict.Letter("ABA_"), 2, func(leave []dict.Letter) {
+		keys = append(keys, Key(leave))
+	})
+	assert.Equal(t, []string{"A", "AA", "AB", "A?", "B", "B?", "?"}, keys)
+
+	// The standard tiles, with two blanks, make 914,624 leaves of one to six
+	// tiles.
+	var n int
+	Each(append(dict.LettersDist(), dict.Blank, dict.Blank), 6,
+		func(leave []dict.Letter) {
+			n++
+		})
+	assert.Equal(t, 914624, n)
+}
@@ -1,7 +1,6 @@
 package move
 
 import (
-	"fmt"
 	. "github.com/tmazeika/scrabble-go/internal/dict"
 )
 
@@ -12,12 +11,29 @@ const (
 	DirDown
 )
 
+// Placement is a tile that a move puts on the board. Letter is the letter
+// the tile stands for, in uppercase, even when it is a blank.
+type Placement struct {
+	Row    int
+	Col    int
+	Letter Letter
+	Blank  bool
+}
+
+// Move is a play of Word, read from Row and Col in direction Dir. Word
+// includes the letters already on the board that the play goes through, and
+// Tiles holds just the tiles put down from the rack, in order along Word.
+// Either can be derived from the other with a board; see board.Complete.
+// A Skip move puts nothing on the board; it is a pass, or an exchange of the
+// letters in Exchange (with Blank for a blank) if there are any.
 type Move struct {
-	Skip bool
-	Row  int
-	Col  int
-	Dir  Dir
-	Word Word
+	Skip     bool
+	Row      int
+	Col      int
+	Dir      Dir
+	Word     Word
+	Tiles    []Placement
+	Exchange []Letter
 }
 
 func (m Move) Transposed() Move {
@@ -27,16 +43,42 @@ func (m Move) Transposed() Move {
 	} else {
 		m.Dir = DirAcross
 	}
+	if m.Tiles != nil {
+		tiles := make([]Placement, len(m.Tiles))
+		for i, p := range m.Tiles {
+			p.Row, p.Col = p.Col, p.Row
+			tiles[i] = p
+		}
+		m.Tiles = tiles
+	}
 	return m
 }
 
-func (m Move) String() string {
+// TilesUsed returns the letters that m takes from the rack, with Blank for
+// each blank. For an exchange, they are the letters put back in the bag.
+func (m Move) TilesUsed() []Letter {
 	if m.Skip {
-		return "skip"
+		return append([]Letter{}, m.Exchange...)
 	}
-	dirStr := "across"
-	if m.Dir == DirDown {
-		dirStr = "down"
+	ls := make([]Letter, len(m.Tiles))
+	for i, p := range m.Tiles {
+		if p.Blank {
+			ls[i] = Blank
+		} else {
+			ls[i] = p.Letter
+		}
+	}
+	return ls
+}
+
+// Leave returns what is left of rack after m is played from it.
+func (m Move) Leave(rack []Letter) []Letter {
+	leave := make([]Letter, len(rack))
+	copy(leave, rack)
+	for _, l := range m.TilesUsed() {
+		if Contains(leave, l) {
+			leave = Remove(leave, l)
+		}
 	}
-	return fmt.Sprintf("(%x,%x) %s: %s", m.Row, m.Col, dirStr, m.Word)
+	return leave
 }
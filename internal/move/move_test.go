@@ -0,0 +1,36 @@
+package move
+
+import (
+	"github.com/stretchr/testify/assert"
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+	"testing"
+)
+
+func TestMove_Transposed(t *testing.T) {
+	m := Move{Row: 7, Col: 5, Dir: DirAcross, Word: "CAT", Tiles: []Placement{
+		{Row: 7, Col: 5, Letter: 'C'},
+		{Row: 7, Col: 7, Letter: 'T', Blank: true},
+	}}
+	mt := m.Transposed()
+	assert.Equal(t, Move{Row: 5, Col: 7, Dir: DirDown, Word: "CAT",
+		Tiles: []Placement{
+			{Row: 5, Col: 7, Letter: 'C'},
+			{Row: 7, Col: 7, Letter: 'T', Blank: true},
+		}}, mt)
+	assert.Equal(t, 5, m.Tiles[0].Col)
+	assert.Equal(t, m, mt.Transposed())
+}
+
+func TestMove_TilesUsed(t *testing.T) {
+	m := Move{Word: "QUIT", Tiles: []Placement{
+		{Letter: 'Q'},
+		{Letter: 'U', Blank: true},
+		{Letter: 'T'},
+	}}
+	assert.Equal(t, []Letter{'Q', Blank, 'T'}, m.TilesUsed())
+	assert.Equal(t, []Letter{'A', 'T', 'E'},
+		m.Leave([]Letter("QATTE_")))
+	assert.Empty(t, Move{Skip: true}.TilesUsed())
+	exchange := Move{Skip: true, Exchange: []Letter{'Q', Blank}}
+	assert.Equal(t, []Letter{'Q', 'T'}, exchange.Leave([]Letter("Q_QT")))
+}
@@ -0,0 +1,151 @@
+package move
+
+import (
+	"fmt"
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+	"strconv"
+	"strings"
+)
+
+// PlayThrough stands in a Word for whatever letter is already on the board
+// at that square.
+const PlayThrough = '.'
+
+// Parse reads a move in the standard notation: "8H WORD" is played across
+// from row 8, column H, and "H8 WORD" down from the same square. Lowercase
+// letters are blanks. Letters already on the board may be put in
+// parentheses, as in "8H CA(T)S", or written as dots ("8H CA.S"); either way
+// it takes a board to tell which squares the word covers (see
+// board.Complete). A lone "-" is a pass and "-ABC" exchanges A, B and C,
+// with '?' or '_' for a blank.
+func Parse(s string) (Move, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "-") {
+		m := Move{Skip: true}
+		for _, r := range s[1:] {
+			switch {
+			case r == '?' || r == rune(Blank):
+				m.Exchange = append(m.Exchange, Blank)
+			case 'A' <= r && r <= 'Z' || 'a' <= r && r <= 'z':
+				m.Exchange = append(m.Exchange, Letter(r).Upper())
+			default:
+				return Move{}, fmt.Errorf("bad letter %q to exchange", r)
+			}
+		}
+		return m, nil
+	}
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return Move{}, fmt.Errorf("bad move %q: want a square and a word", s)
+	}
+	m, err := parseSquare(fields[0])
+	if err != nil {
+		return Move{}, err
+	}
+	m.Word, err = parseWord(fields[1])
+	return m, err
+}
+
+func parseSquare(s string) (Move, error) {
+	var m Move
+	var rowStr string
+	col := Letter(s[0]).Upper()
+	if 'A' <= col && col <= 'Z' {
+		m.Dir = DirDown
+		rowStr = s[1:]
+	} else {
+		col = Letter(s[len(s)-1]).Upper()
+		rowStr = s[:len(s)-1]
+	}
+	row, err := strconv.Atoi(rowStr)
+	if err != nil || row < 1 || col < 'A' || col > 'Z' ||
+		strings.HasPrefix(rowStr, "+") {
+		return Move{}, fmt.Errorf("bad square %q", s)
+	}
+	m.Row, m.Col = row-1, int(col-'A')
+	return m, nil
+}
+
+func parseWord(s string) (Word, error) {
+	var word []Letter
+	through := false
+	for _, r := range s {
+		switch {
+		case r == '(' && !through:
+			through = true
+		case r == ')' && through:
+			through = false
+		case r == PlayThrough && !through:
+			word = append(word, PlayThrough)
+		case 'A' <= r && r <= 'Z' || 'a' <= r && r <= 'z':
+			if through {
+				word = append(word, Letter(r).Upper())
+			} else {
+				word = append(word, Letter(r))
+			}
+		default:
+			return "", fmt.Errorf("bad word %q", s)
+		}
+	}
+	if through || len(word) == 0 {
+		return "", fmt.Errorf("bad word %q", s)
+	}
+	return Word(string(word)), nil
+}
+
+// String returns m in the notation read by Parse. If m has its Tiles, the
+// letters it plays through are put in parentheses and blanks are lowercase;
+// otherwise Word is written as it is.
+func (m Move) String() string {
+	if m.Skip {
+		var buf strings.Builder
+		buf.WriteByte('-')
+		for _, l := range m.Exchange {
+			if l == Blank {
+				l = '?'
+			}
+			buf.WriteRune(rune(l))
+		}
+		return buf.String()
+	}
+	var buf strings.Builder
+	row, col := strconv.Itoa(m.Row+1), string(rune('A'+m.Col))
+	if m.Dir == DirDown {
+		buf.WriteString(col + row)
+	} else {
+		buf.WriteString(row + col)
+	}
+	buf.WriteByte(' ')
+	if m.Tiles == nil {
+		buf.WriteString(string(m.Word))
+		return buf.String()
+	}
+	dr, dc := 0, 1
+	if m.Dir == DirDown {
+		dr, dc = 1, 0
+	}
+	tiles, through := m.Tiles, false
+	for i, r := range m.Word {
+		placed := len(tiles) > 0 && tiles[0].Row == m.Row+i*dr &&
+			tiles[0].Col == m.Col+i*dc
+		if placed == through {
+			if through {
+				buf.WriteByte(')')
+			} else {
+				buf.WriteByte('(')
+			}
+			through = !through
+		}
+		if placed {
+			if tiles[0].Blank {
+				r = rune(Letter(r).Lower())
+			}
+			tiles = tiles[1:]
+		}
+		buf.WriteRune(r)
+	}
+	if through {
+		buf.WriteByte(')')
+	}
+	return buf.String()
+}
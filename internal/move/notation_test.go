@@ -0,0 +1,53 @@
+package move
+
+import (
+	"github.com/stretchr/testify/assert"
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Move
+	}{
+		{"8H CAT", Move{Row: 7, Col: 7, Word: "CAT"}},
+		{"H8 CAT", Move{Row: 7, Col: 7, Dir: DirDown, Word: "CAT"}},
+		{"15o cAT", Move{Row: 14, Col: 14, Word: "cAT"}},
+		{"a1 CA(t)S", Move{Dir: DirDown, Word: "CATS"}},
+		{" 10B (CA)T.. ", Move{Row: 9, Col: 1, Word: "CAT.."}},
+		{"-", Move{Skip: true}},
+		{"-Aq?_", Move{Skip: true, Exchange: []Letter{'A', 'Q', Blank, Blank}}},
+	}
+	for _, test := range tests {
+		m, err := Parse(test.s)
+		assert.Nil(t, err, test.s)
+		assert.Equal(t, test.want, m, test.s)
+	}
+	for _, s := range []string{"", "8H", "8 CAT", "H CAT", "0H CAT", "8HH CAT",
+		"+8H CAT", "8H CA(T", "8H CA)T", "8H C(A.)T", "8H ()", "8H C1T",
+		"8H CAT DOG", "-A1"} {
+		_, err := Parse(s)
+		assert.NotNil(t, err, s)
+	}
+}
+
+func TestMove_String(t *testing.T) {
+	m := Move{Row: 7, Col: 5, Word: "SCATTER", Tiles: []Placement{
+		{Row: 7, Col: 5, Letter: 'S'},
+		{Row: 7, Col: 8, Letter: 'T', Blank: true},
+		{Row: 7, Col: 11, Letter: 'R'},
+	}}
+	assert.Equal(t, "8F S(CA)t(TE)R", m.String())
+	assert.Equal(t, "H6 S(CA)t(TE)R", m.Transposed().String())
+	assert.Equal(t, "12A SCATTER", Move{Row: 11, Word: "SCATTER"}.String())
+	assert.Equal(t, "8H (CAT)S", Move{Row: 7, Col: 7, Word: "CATS",
+		Tiles: []Placement{{Row: 7, Col: 10, Letter: 'S'}}}.String())
+	assert.Equal(t, "-", Move{Skip: true}.String())
+	assert.Equal(t, "-QU?",
+		Move{Skip: true, Exchange: []Letter{'Q', 'U', Blank}}.String())
+
+	m2, err := Parse(m.String())
+	assert.Nil(t, err)
+	assert.Equal(t, Move{Row: 7, Col: 5, Word: "SCAtTER"}, m2)
+}
@@ -1,11 +1,10 @@
 package scrabble
 
 import (
-	"github.com/tmazeika/scrabble-go/internal/board"
 	. "github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/leaves"
 	. "github.com/tmazeika/scrabble-go/internal/move"
 	"math/rand"
-	"sync"
 )
 
 type StrategyFunc func(game *Game, moves []Move) Move
@@ -44,152 +43,85 @@ func MostPointsStrategy(game *Game, moves []Move) Move {
 	return best
 }
 
-func NewMCTSStrategy(iterations, pickTop int, c float64) StrategyFunc {
+// Equity returns what m is worth to the current player of game: its points,
+// plus the value in table of what it keeps on rack while there are tiles
+// left to draw.
+func Equity(game *Game, rack []Letter, m Move, table *leaves.Table) float64 {
+	if m.Tiles == nil && !m.Skip {
+		var err error
+		if m, err = game.Board.Complete(m); err != nil {
+			panic(err)
+		}
+	}
+	points := float64(game.Board.Points(m))
+	if game.Bag.Empty() {
+		return points
+	}
+	return points + table.Value(m.Leave(rack))
+}
+
+// NewEquityStrategy returns a strategy that plays the move with the most
+// Equity.
+func NewEquityStrategy(table *leaves.Table) StrategyFunc {
 	return func(game *Game, moves []Move) Move {
 		if len(moves) == 0 {
 			return Move{Skip: true}
 		}
-		return mcts(game, moves, iterations, pickTop, c)
+		rack := game.CurrentPlayer().Rack()
+		best, bestEquity := moves[0], Equity(game, rack, moves[0], table)
+		for _, m := range moves[1:] {
+			equity := Equity(game, rack, m, table)
+			if equity > bestEquity {
+				best, bestEquity = m, equity
+			}
+		}
+		return best
 	}
 }
 
+// NewMCTSStrategy returns a strategy that plays by an MCTS running
+// iterations for each move, reusing its tree from move to move.
+func NewMCTSStrategy(iterations, pickTop int, c float64) StrategyFunc {
+	return NewMCTS(MCTSOptions{
+		Iterations: iterations,
+		PickTop:    pickTop,
+		C:          c,
+		Reuse:      true,
+	}).Play
+}
+
 type ComputerPlayer struct {
 	basePlayer
 	strategy StrategyFunc
+	// endgame, if set, is how endgames are solved instead of using strategy.
+	endgame *EndgameOptions
 }
 
+// NewComputerPlayer returns a player that plays by strategy until the bag is
+// empty, and then by SolveEndgame with DefaultEndgameOptions if there is one
+// opponent.
 func NewComputerPlayer(name string, strategy StrategyFunc) *ComputerPlayer {
+	opts := DefaultEndgameOptions
 	return &ComputerPlayer{
 		basePlayer{
 			name: name,
 		},
 		strategy,
+		&opts,
 	}
 }
 
-func (p *ComputerPlayer) Play(game *Game) Move {
-	return p.strategy(game, AllMoves(game.Dict, game.Board, p.rack))
-}
-
-func mergeMoves(cs ...<-chan Move) <-chan Move {
-	out := make(chan Move)
-	var wg sync.WaitGroup
-	wg.Add(len(cs))
-	for _, in := range cs {
-		in := in
-		go func() {
-			for m := range in {
-				out <- m
-			}
-			wg.Done()
-		}()
-	}
-	go func() {
-		wg.Wait()
-		close(out)
-	}()
-	return out
+// SetEndgame sets how p solves endgames, or with nil makes it play them by
+// its strategy.
+func (p *ComputerPlayer) SetEndgame(opts *EndgameOptions) {
+	p.endgame = opts
 }
 
-func transposeMoves(in <-chan Move) <-chan Move {
-	out := make(chan Move)
-	go func() {
-		for m := range in {
-			out <- m.Transposed()
-		}
-		close(out)
-	}()
-	return out
-}
-
-func AllMoves(dict *Node, b *board.Board, rack []Letter) []Move {
-	b.SetYCrossChecks(dict)
-	bt := b.Transposed()
-	bt.SetYCrossChecks(dict)
-	across := getAcrossMoves(dict, b, rack)
-	down := transposeMoves(getAcrossMoves(dict, bt, rack))
-	var moves []Move
-	for m := range mergeMoves(across, down) {
-		moves = append(moves, m)
-	}
-	return moves
-}
-
-func getAcrossMoves(dict *Node, b *board.Board, rack []Letter) <-chan Move {
-	out := make(chan Move)
-	go func() {
-		anchors := b.Anchors()
-		if len(anchors) == 0 {
-			anchors = []*board.Tile{b.Center()}
-		}
-		var wg sync.WaitGroup
-		wg.Add(len(anchors))
-		for _, a := range anchors {
-			a := a
-			go func() {
-				defer wg.Done()
-				getAcrossAnchorMoves(dict, b, rack, a, getK(a), out)
-			}()
-		}
-		wg.Wait()
-		close(out)
-	}()
-	return out
-}
-
-func getK(anchor *board.Tile) int {
-	var k int
-	for t := anchor.Left(); t != nil && t.Empty() && t.EmptyAround();
-	t = t.Left() {
-		k++
-	}
-	return k
-}
-
-func getAcrossAnchorMoves(dict *Node, b *board.Board, rack []Letter,
-	anchor *board.Tile, k int, out chan<- Move) {
-	if anchor.Left().Empty() {
-		leftPart(b, anchor, rack, "", dict, k, out)
-	} else {
-		left := anchor.GatherLeft()
-		extendRight(b, anchor, rack, left, dict.Search(left), anchor, out)
-	}
-}
-
-func leftPart(b *board.Board, anchor *board.Tile, rack []Letter,
-	partialWord Word, node *Node, limit int, out chan<- Move) {
-	extendRight(b, anchor, rack, partialWord, node, anchor, out)
-	if limit > 0 {
-		for l, n := range node.Edges() {
-			if Contains(rack, l) {
-				leftPart(b, anchor, Remove(rack, l), partialWord.Append(l), n,
-					limit-1, out)
-			}
-		}
-	}
-}
-
-func extendRight(b *board.Board, anchor *board.Tile, rack []Letter,
-	partialWord Word, node *Node, square *board.Tile, out chan<- Move) {
-	if square.Empty() {
-		if node.Accept() && anchor.Col() < square.Col() {
-			out <- Move{
-				Row:  square.Row(),
-				Col:  square.Col() - len(partialWord),
-				Dir:  DirAcross,
-				Word: partialWord,
-			}
-		}
-		for l, n := range node.Edges() {
-			if Contains(rack, l) && square.InYCrossCheck(l) &&
-				square.Right() != nil {
-				extendRight(b, anchor, Remove(rack, l),
-					partialWord.Append(l), n, square.Right(), out)
-			}
+func (p *ComputerPlayer) Play(game *Game) Move {
+	if p.endgame != nil && game.Bag.Empty() && len(game.Players) == 2 {
+		if r := SolveEndgame(game, *p.endgame); len(r.Moves) > 0 {
+			return r.Moves[0]
 		}
-	} else if n, ok := node.Edges()[square.Letter()];
-		ok && square.Right() != nil {
-		extendRight(b, anchor, rack, partialWord.Append(square.Letter()), n,
-			square.Right(), out)
 	}
+	return p.strategy(game, game.AllMoves(p.rack))
 }
@@ -1,20 +1,23 @@
 package scrabble
 
 import (
+	"github.com/stretchr/testify/assert"
 	"github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/leaves"
+	"github.com/tmazeika/scrabble-go/internal/move"
 	"math/rand"
+	"path/filepath"
 	"testing"
 )
 
 func BenchmarkComputerPlayer_Play(b *testing.B) {
-	println("Here")
 	rand.Seed(1)
-	d, err := dict.Load(dict.Dict)
+	d, err := dict.Load(filepath.Join("..", "..", dict.Dict))
 	if err != nil {
 		panic(err)
 	}
-	player1 := NewComputerPlayer("P1", NewRandomStrategy())
-	player2 := NewComputerPlayer("P2", NewRandomStrategy())
+	player1 := NewComputerPlayer("P1", RandomStrategy)
+	player2 := NewComputerPlayer("P2", RandomStrategy)
 	game := NewGame(d, player1, player2)
 	_, err = game.PlayRound()
 	if err != nil {
@@ -25,3 +28,30 @@ func BenchmarkComputerPlayer_Play(b *testing.B) {
 		_ = player2.Play(game)
 	}
 }
+
+func TestEquityStrategy(t *testing.T) {
+	rand.Seed(0)
+	p1 := NewComputerPlayer("P1", MostPointsStrategy)
+	p2 := NewComputerPlayer("P2", MostPointsStrategy)
+	game := NewGame(dict.NewNode(), p1, p2)
+	p1.SetRack([]dict.Letter("AST"))
+	var moves []move.Move
+	for _, s := range []string{"8G SAT", "8H AS", "8H TA"} {
+		m, err := move.Parse(s)
+		assert.Nil(t, err)
+		moves = append(moves, m)
+	}
+	equity := NewEquityStrategy(leaves.Heuristic())
+
+	// SAT scores 6 and uses up the S, while TA scores 4 and keeps it.
+	assert.Equal(t, moves[0], MostPointsStrategy(game, moves))
+	assert.Equal(t, moves[2], equity(game, moves))
+	assert.Equal(t, 6.0, Equity(game, p1.Rack(), moves[0], leaves.Heuristic()))
+	assert.Equal(t, 4+7.5, Equity(game, p1.Rack(), moves[2],
+		leaves.Heuristic()))
+
+	// Leaves are worth nothing once the bag is empty.
+	game.Bag.Draw(100)
+	assert.Equal(t, moves[0], equity(game, moves))
+	assert.Equal(t, move.Move{Skip: true}, equity(game, nil))
+}
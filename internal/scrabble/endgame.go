@@ -0,0 +1,316 @@
+package scrabble
+
+import (
+	"errors"
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+	. "github.com/tmazeika/scrabble-go/internal/move"
+	"github.com/tmazeika/scrabble-go/internal/ttable"
+	"math"
+	"sort"
+	"time"
+)
+
+// EndgameOptions configures SolveEndgame.
+type EndgameOptions struct {
+	// MaxDepth is the most plies searched, or zero for no limit.
+	MaxDepth int
+	// Width, if set, is how many of the highest scoring moves are searched
+	// in each position, along with passing. The result is then not Exact.
+	Width int
+	// Time is the most time spent searching, or zero for no limit. The
+	// deepest search finished in time is used, and the first always
+	// finishes.
+	Time time.Duration
+	// Table, if set, is the transposition table, which may be shared by
+	// searches of the same game with the same Width.
+	Table *ttable.Table
+}
+
+// DefaultEndgameOptions is how a ComputerPlayer solves endgames unless told
+// otherwise.
+var DefaultEndgameOptions = EndgameOptions{Time: time.Second}
+
+// EndgameResult is what SolveEndgame found.
+type EndgameResult struct {
+	// Moves is the best line of play, starting with the current player's
+	// move.
+	Moves []Move
+	// Spread is what the current player's points less the opponent's will
+	// be after Moves, if Exact, or after Depth plies.
+	Spread int
+	Depth  int
+	// Exact is set when the search reached the end of the game in every
+	// line, so no better line exists.
+	Exact bool
+}
+
+// errEndgameTime stops a search that has run out of time.
+var errEndgameTime = errors.New("out of time")
+
+// SolveEndgame searches a game between two players with an empty bag, where
+// both racks are known, for the line of play that ends with the best spread
+// for the current player. It uses iterative-deepening alpha-beta search,
+// trying the move the transposition table remembers and then the highest
+// scoring moves first. If both players pass in a row the game is taken to
+// end, each losing the points on their rack. game is not changed.
+func SolveEndgame(game *Game, opts EndgameOptions) EndgameResult {
+	if len(game.Players) != 2 || !game.Bag.Empty() {
+		panic("endgame needs two players and an empty bag")
+	}
+	s := endgameSearch{
+		state: game.AICopy(MostPointsStrategy),
+		table: opts.Table,
+		width: opts.Width,
+	}
+	s.state.Gen = GenOptions{Pool: game.Gen.Pool, Parallelism: 1}
+	if s.table == nil {
+		s.table = ttable.New(1 << 16)
+	}
+	if opts.Time > 0 {
+		s.deadline = time.Now().Add(opts.Time)
+	}
+	var result EndgameResult
+	for depth := 1; opts.MaxDepth <= 0 || depth <= opts.MaxDepth; depth++ {
+		s.cutoff = false
+		s.timed = depth > 1 && opts.Time > 0
+		v, line, err := s.negamax(depth, math.MinInt32, math.MaxInt32, false)
+		if err != nil {
+			break
+		}
+		result = EndgameResult{
+			Moves:  s.extend(line),
+			Spread: spread(s.state) + v,
+			Depth:  depth,
+			Exact:  !s.cutoff && !s.narrowed,
+		}
+		if !s.cutoff {
+			break
+		}
+	}
+	return result
+}
+
+type endgameSearch struct {
+	state    *Game
+	table    *ttable.Table
+	width    int
+	deadline time.Time
+	// timed is set when the search may run out of time.
+	timed bool
+	// cutoff is set when a line was cut short before the end of the game,
+	// and narrowed when moves were left out for width.
+	cutoff   bool
+	narrowed bool
+}
+
+// exactDepth is the depth of a transposition table entry for a position
+// that was searched to the end of the game.
+const exactDepth = math.MaxInt32
+
+// passedKey is mixed into the hash of a position reached by a pass, since
+// another pass then ends the game.
+const passedKey = 0x9e3779b97f4a7c15
+
+// negamax returns how much the current player's spread will change from here
+// with the best play by both players up to depth plies, and the line that
+// gets it.
+func (s *endgameSearch) negamax(depth, alpha, beta int, passed bool) (int,
+	[]Move, error) {
+	if s.timed && time.Now().After(s.deadline) {
+		return 0, nil, errEndgameTime
+	}
+	state := s.state
+	before := spread(state)
+	// Checking for a game with no moves left is slow, and two passes end
+	// it anyway.
+	if len(otherPlayer(state).Rack()) == 0 && state.Over() {
+		return spread(state) - before, nil, nil
+	}
+	if depth == 0 {
+		s.cutoff = true
+		return 0, nil, nil
+	}
+	key := state.Hash()
+	if passed {
+		key ^= passedKey
+	}
+	var best Move
+	hasBest := false
+	if e, ok := s.table.Get(key); ok {
+		best, hasBest = e.Move, true
+		if e.Depth >= depth && (e.Bound == ttable.Exact ||
+			e.Bound == ttable.Lower && e.Value >= beta ||
+			e.Bound == ttable.Upper && e.Value <= alpha) {
+			s.cutoff = s.cutoff || e.Depth < exactDepth
+			return e.Value, []Move{e.Move}, nil
+		}
+	}
+
+	rack := state.CurrentPlayer().Rack()
+	alpha0, cutoff := alpha, s.cutoff
+	s.cutoff = false
+	bestValue, bestLine := math.MinInt32, []Move(nil)
+	for _, m := range s.orderedMoves(best, hasBest) {
+		var v int
+		var line []Move
+		switch {
+		case m.Skip && passed:
+			v = rackPoints(otherPlayer(state).Rack()) -
+				rackPoints(state.CurrentPlayer().Rack())
+		case depth == 1 && !m.Skip:
+			// Save playing the move just to find the game not over.
+			v = state.Board.Points(m)
+			if out := len(m.TilesUsed()) == len(rack); out {
+				v += 2 * rackPoints(otherPlayer(state).Rack())
+			} else {
+				s.cutoff = true
+			}
+		default:
+			u, err := state.PlayMove(m)
+			if err != nil {
+				panic(err)
+			}
+			gain := -spread(state) - before
+			child, childLine, err := s.negamax(depth-1, gain-beta, gain-alpha,
+				m.Skip)
+			state.UndoMove(u)
+			if err != nil {
+				return 0, nil, err
+			}
+			v, line = gain-child, childLine
+		}
+		if v > bestValue {
+			bestValue, bestLine = v, append([]Move{m}, line...)
+		}
+		if v > alpha {
+			alpha = v
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+
+	e := ttable.Entry{Key: key, Depth: depth, Value: bestValue,
+		Move: bestLine[0]}
+	if !s.cutoff {
+		e.Depth = exactDepth
+	}
+	switch {
+	case bestValue <= alpha0:
+		e.Bound = ttable.Upper
+	case bestValue >= beta:
+		e.Bound = ttable.Lower
+	default:
+		e.Bound = ttable.Exact
+	}
+	s.table.Put(e)
+	s.cutoff = s.cutoff || cutoff
+	return bestValue, bestLine, nil
+}
+
+// extend follows the moves remembered in the transposition table on from the
+// end of line, where the search found the rest in the table, to the end of
+// the game or as far as the table goes.
+func (s *endgameSearch) extend(line []Move) []Move {
+	state := s.state
+	var undos []Undo
+	defer func() {
+		for i := len(undos) - 1; i >= 0; i-- {
+			state.UndoMove(undos[i])
+		}
+	}()
+	passed := false
+	for i, m := range line {
+		if i == len(line)-1 && m.Skip && passed {
+			return line
+		}
+		u, err := state.PlayMove(m)
+		if err != nil {
+			panic(err)
+		}
+		undos = append(undos, u)
+		passed = m.Skip
+	}
+	for !state.Over() {
+		key := state.Hash()
+		if passed {
+			key ^= passedKey
+		}
+		e, ok := s.table.Get(key)
+		if !ok {
+			break
+		}
+		line = append(line, e.Move)
+		if e.Move.Skip && passed {
+			break
+		}
+		u, err := state.PlayMove(e.Move)
+		if err != nil {
+			panic(err)
+		}
+		undos = append(undos, u)
+		passed = e.Move.Skip
+	}
+	return line
+}
+
+// orderedMoves returns the moves of the current player, and a pass, in the
+// order to search them: best first if there is one, then moves that go out,
+// then by points.
+func (s *endgameSearch) orderedMoves(best Move, hasBest bool) []Move {
+	state := s.state
+	rack := state.CurrentPlayer().Rack()
+	moves := state.AllMoves(rack)
+	points := make([]int, len(moves))
+	for i, m := range moves {
+		points[i] = state.Board.Points(m)
+		if len(m.TilesUsed()) == len(rack) {
+			points[i] += 1000
+		}
+	}
+	sort.Stable(byPoints{moves, points})
+	if s.width > 0 && len(moves) > s.width {
+		moves = moves[:s.width]
+		s.narrowed = true
+	}
+	moves = append(moves, Move{Skip: true})
+	if hasBest {
+		for i, m := range moves {
+			if m.String() == best.String() {
+				copy(moves[1:i+1], moves[:i])
+				moves[0] = m
+				break
+			}
+		}
+	}
+	return moves
+}
+
+type byPoints struct {
+	moves  []Move
+	points []int
+}
+
+func (b byPoints) Len() int           { return len(b.moves) }
+func (b byPoints) Less(i, j int) bool { return b.points[i] > b.points[j] }
+func (b byPoints) Swap(i, j int) {
+	b.moves[i], b.moves[j] = b.moves[j], b.moves[i]
+	b.points[i], b.points[j] = b.points[j], b.points[i]
+}
+
+// spread returns the current player's points less the other player's.
+func spread(g *Game) int {
+	return g.CurrentPlayer().Points() - otherPlayer(g).Points()
+}
+
+func otherPlayer(g *Game) Player {
+	return g.Players[(g.Round+1)%len(g.Players)]
+}
+
+func rackPoints(rack []Letter) int {
+	var n int
+	for _, l := range rack {
+		n += l.Points()
+	}
+	return n
+}
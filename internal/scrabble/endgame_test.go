@@ -0,0 +1,130 @@
+package scrabble
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/tmazeika/scrabble-go/internal/board"
+	"github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/move"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func endgameFixture(rack1, rack2 string) *Game {
+	d := dict.NewNode()
+	for _, w := range []dict.Word{"AT", "TA", "CAT", "CATS", "SCAT", "ACT",
+		"ACTS", "SAT", "AS", "EAT", "TEA", "ATE", "SEA", "SET", "ES", "QI",
+		"QAT", "QATS", "ZA", "ZAS", "ZEA", "ZEAS", "IS", "ITS", "SIT"} {
+		d.Insert(w)
+	}
+	p1 := NewComputerPlayer("P1", MostPointsStrategy)
+	p2 := NewComputerPlayer("P2", MostPointsStrategy)
+	g := NewGame(d, p1, p2)
+	g.Gen = GenOptions{Parallelism: 1}
+	g.Bag.Draw(100)
+	g.Board = board.MustFromString(`
+		...............
+		...............
+		...............
+		...............
+		...............
+		...............
+		...............
+		......CAT......
+		...............
+		...............
+		...............
+		...............
+		...............
+		...............
+		...............`)
+	p1.SetRack([]dict.Letter(rack1))
+	p2.SetRack([]dict.Letter(rack2))
+	return g
+}
+
+// bruteEndgame returns the best spread for the current player of g by trying
+// every line, with two passes in a row ending the game.
+func bruteEndgame(g *Game, passed bool) int {
+	if g.Over() {
+		return spread(g)
+	}
+	best := -1 << 30
+	moves := append(g.AllMoves(g.CurrentPlayer().Rack()), move.Move{Skip: true})
+	for _, m := range moves {
+		var v int
+		if m.Skip && passed {
+			v = spread(g) - rackPoints(g.CurrentPlayer().Rack()) +
+				rackPoints(otherPlayer(g).Rack())
+		} else {
+			u, err := g.PlayMove(m)
+			if err != nil {
+				panic(err)
+			}
+			v = -bruteEndgame(g, m.Skip)
+			g.UndoMove(u)
+		}
+		if v > best {
+			best = v
+		}
+	}
+	return best
+}
+
+func TestSolveEndgame(t *testing.T) {
+	rand.Seed(0)
+	for _, racks := range [][2]string{
+		{"S", "Q"},
+		{"SEQ", "AZI"},
+		{"QI", "SAZ"},
+		{"ZEAS", "QIT"},
+		{"TEA", "SSQ"},
+	} {
+		g := endgameFixture(racks[0], racks[1])
+		g.Players[0].AddPoints(10)
+		r := SolveEndgame(g, EndgameOptions{})
+		assert.True(t, r.Exact, racks)
+		assert.Equal(t, bruteEndgame(g, false), r.Spread, racks)
+		assert.Equal(t, 10, g.Players[0].Points())
+		assert.Equal(t, []dict.Letter(racks[0]), g.Players[0].Rack())
+
+		// The line can be played out and ends with the spread found.
+		for _, m := range r.Moves {
+			_, err := g.PlayMove(m)
+			assert.Nil(t, err, racks)
+		}
+		over := g.Over()
+		spread := g.Players[0].Points() - g.Players[1].Points()
+		if !over {
+			n := len(r.Moves)
+			assert.True(t, r.Moves[n-2].Skip && r.Moves[n-1].Skip, racks)
+			spread += rackPoints(g.Players[1].Rack()) -
+				rackPoints(g.Players[0].Rack())
+		}
+		assert.Equal(t, r.Spread, spread, racks)
+	}
+}
+
+func TestSolveEndgame_Limits(t *testing.T) {
+	g := endgameFixture("ZEAS", "QIT")
+	r := SolveEndgame(g, EndgameOptions{MaxDepth: 1})
+	assert.Equal(t, 1, r.Depth)
+	assert.False(t, r.Exact)
+	assert.Len(t, r.Moves, 1)
+
+	r = SolveEndgame(g, EndgameOptions{Width: 1})
+	assert.False(t, r.Exact)
+	assert.True(t, r.Spread <= SolveEndgame(g, EndgameOptions{}).Spread)
+
+	r = SolveEndgame(g, EndgameOptions{Time: time.Nanosecond})
+	assert.True(t, r.Depth >= 1)
+	assert.NotEmpty(t, r.Moves)
+}
+
+func TestComputerPlayer_Endgame(t *testing.T) {
+	g := endgameFixture("ZEAS", "QIT")
+	p := g.CurrentPlayer().(*ComputerPlayer)
+	assert.Equal(t, SolveEndgame(g, DefaultEndgameOptions).Moves[0], p.Play(g))
+	p.SetEndgame(nil)
+	assert.Equal(t, MostPointsStrategy(g, g.AllMoves(p.Rack())), p.Play(g))
+}
@@ -0,0 +1,80 @@
+package scrabble
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/tmazeika/scrabble-go/internal/board"
+	"github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/fixture"
+	"github.com/tmazeika/scrabble-go/internal/move"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestAllMoves_Fixtures generates the moves for the positions in
+// testdata/movegen. Each file has a "words" line giving the lexicon and a
+// "rack" line, then a board, then the expected moves: a move in notation
+// with its score must be generated, "no" and a move must not be, and
+// "count" gives how many moves there are.
+func TestAllMoves_Fixtures(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("testdata", "movegen", "*.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.NotEmpty(t, paths)
+	for _, path := range paths {
+		paragraphs, err := fixture.Read(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(paragraphs) != 3 {
+			t.Fatalf("%s: want a lexicon and rack, a board and moves", path)
+		}
+		d := dict.NewNode()
+		var rack []dict.Letter
+		for _, line := range strings.Split(paragraphs[0], "\n") {
+			fields := strings.Fields(line)
+			switch fields[0] {
+			case "words":
+				for _, w := range fields[1:] {
+					d.Insert(dict.Word(w))
+				}
+			case "rack":
+				rack = []dict.Letter(fields[1])
+			}
+		}
+		b, err := board.FromString(paragraphs[1])
+		if err != nil {
+			t.Fatalf("%s: %v", path, err)
+		}
+
+		moves := AllMoves(d, b, rack)
+		generated := make(map[string]bool)
+		for _, m := range moves {
+			generated[m.String()] = true
+		}
+		for _, line := range strings.Split(paragraphs[2], "\n") {
+			if strings.HasPrefix(line, "count ") {
+				n, _ := strconv.Atoi(line[len("count "):])
+				assert.Len(t, moves, n, "%s: %v", path, moves)
+				continue
+			}
+			want := !strings.HasPrefix(line, "no ")
+			s := strings.TrimPrefix(line, "no ")
+			points := -1
+			if i := strings.LastIndexByte(s, ' '); want {
+				points, _ = strconv.Atoi(s[i+1:])
+				s = s[:i]
+			}
+			m, err := move.Parse(s)
+			assert.Nil(t, err, "%s: %s", path, line)
+			m, err = b.Complete(m)
+			assert.Nil(t, err, "%s: %s", path, line)
+			assert.Equal(t, want, generated[m.String()], "%s: %s", path, line)
+			if want {
+				assert.Equal(t, points, b.Points(m), "%s: %s", path, line)
+			}
+		}
+	}
+}
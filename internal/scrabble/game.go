@@ -7,6 +7,9 @@ import (
 	. "github.com/tmazeika/scrabble-go/internal/dict"
 	. "github.com/tmazeika/scrabble-go/internal/move"
 	"github.com/tmazeika/scrabble-go/internal/rules"
+	"github.com/tmazeika/scrabble-go/internal/zobrist"
+	"math/rand"
+	"sort"
 	"strings"
 )
 
@@ -16,8 +19,11 @@ type Game struct {
 	Players []Player
 	Dict    *Node
 	Round   int
+	// Gen configures how moves are generated for the players.
+	Gen GenOptions
 
-	over bool
+	over    bool
+	history []Move
 }
 
 func NewGame(dict *Node, players ...Player) *Game {
@@ -47,57 +53,159 @@ func (g *Game) AICopy(strategy StrategyFunc) *Game {
 		Players: players,
 		Dict:    g.Dict,
 		Round:   g.Round,
+		Gen:     g.Gen,
 		over:    g.over,
+		history: append([]Move(nil), g.history...),
 	}
 }
 
+// Unseen returns the tiles that the player at seat cannot see: those in the
+// bag and on the other players' racks.
+func (g *Game) Unseen(seat int) []Letter {
+	unseen := g.Bag.Letters()
+	for i, p := range g.Players {
+		if i != seat {
+			unseen = append(unseen, p.Rack()...)
+		}
+	}
+	return unseen
+}
+
+// Determinize returns an AICopy of g as the player at seat might imagine it,
+// with the tiles unseen by them dealt out at random by r to the other
+// players' racks and to the bag. The deal depends only on r and on what the
+// player can see, and the bag is shuffled by a source seeded from r when
+// tiles are exchanged.
+func (g *Game) Determinize(seat int, strategy StrategyFunc,
+	r *rand.Rand) *Game {
+	g2 := g.AICopy(strategy)
+	g2.deal(seat, g.Unseen(seat), r)
+	return g2
+}
+
+// deal deals unseen, the tiles that the player at seat cannot see, out at
+// random by r to the other players' racks and to the bag of g, in place of
+// those there now, as Determinize does.
+func (g *Game) deal(seat int, unseen []Letter, r *rand.Rand) {
+	unseen = append([]Letter(nil), unseen...)
+	sort.Slice(unseen, func(i, j int) bool {
+		return unseen[i] < unseen[j]
+	})
+	r.Shuffle(len(unseen), func(i, j int) {
+		unseen[i], unseen[j] = unseen[j], unseen[i]
+	})
+	for i, p := range g.Players {
+		if i != seat {
+			n := len(p.Rack())
+			p.SetRack(unseen[:n])
+			unseen = unseen[n:]
+		}
+	}
+	g.Bag = bag.FromLetters(unseen)
+	g.Bag.SetSeed(r.Int63())
+}
+
 func (g *Game) PlayRound() (string, error) {
 	return g.playMove(g.CurrentPlayer().Play(g))
 }
 
 func (g *Game) playMove(m Move) (string, error) {
 	player := g.CurrentPlayer()
+	before := player.Points()
+	if full, err := g.Board.Complete(m); err == nil {
+		m = full
+	}
+	if _, err := g.PlayMove(m); err != nil {
+		return "", err
+	}
+	if len(m.Exchange) > 0 {
+		return fmt.Sprintf("\n%s exchanged %d tiles.\n", player.Name(),
+			len(m.Exchange)), nil
+	}
 	if m.Skip {
-		g.Round++
 		return fmt.Sprintf("\nSkipping %s's turn.\n", player.Name()), nil
 	}
-	b := g.Board
-	// Normalize.
-	if m.Dir == DirDown {
-		b = b.Transposed()
-		m = m.Transposed()
-	}
-	b.SetYCrossChecks(g.Dict)
+	return fmt.Sprintf("\n%s played %v and scored %d points!\n",
+		player.Name(), m, player.Points()-before), nil
+}
 
-	// Validation.
-	if !b.FitsAcross(m.Row, m.Col, len(m.Word)) {
-		return "", fmt.Errorf("move would fall off the board: %v", m)
+// Undo holds what is needed to take back a move played with PlayMove.
+type Undo struct {
+	board   board.Undo
+	bag     bag.State
+	round   int
+	over    bool
+	history int
+	points  []int
+	racks   [][]Letter
+}
+
+// History returns the moves played so far, as completed by Validate.
+func (g *Game) History() []Move {
+	return append([]Move(nil), g.history...)
+}
+
+// PlayMove validates m and plays it for the current player. The returned
+// Undo takes it back with UndoMove, along with anything Over did to the scores
+// since.
+func (g *Game) PlayMove(m Move) (Undo, error) {
+	r, err := g.Validate(m)
+	if err != nil {
+		return Undo{}, err
 	}
-	if !makesValidWords(g.Dict, b, m) {
-		return "", fmt.Errorf("invalid word(s) would be created: %v", m)
+	player := g.CurrentPlayer()
+	u := g.snapshot()
+	if m.Skip && len(m.Exchange) > 0 {
+		player.UseRack(m.Exchange)
+		player.DrawFrom(g.Bag)
+		g.Bag.Return(m.Exchange)
 	}
-	needed := neededFromRack(b, m)
-	if len(needed) == 0 {
-		return "", fmt.Errorf("must put down at least one letter from the rack: %v", m)
+	if !m.Skip {
+		player.AddPoints(r.Score)
+		player.UseRack(r.Move.TilesUsed())
+		player.DrawFrom(g.Bag)
 	}
-	if !player.InRack(needed) {
-		return "", fmt.Errorf("required letters %q are not in rack: %v", needed, m)
+	u.board = g.Board.PlaceMove(r.Move)
+	g.Round++
+	g.history = append(g.history, r.Move)
+	return u, nil
+}
+
+func (g *Game) snapshot() Undo {
+	u := Undo{
+		bag:     g.Bag.State(),
+		round:   g.Round,
+		over:    g.over,
+		history: len(g.history),
+		points:  make([]int, len(g.Players)),
+		racks:   make([][]Letter, len(g.Players)),
 	}
-	if g.Board.Center().Empty() && !passesThroughCenter(b, m) {
-		return "", fmt.Errorf("first move must pass through the center: %v", m)
+	for i, p := range g.Players {
+		u.points[i] = p.Points()
+		u.racks[i] = p.Rack()
 	}
-	if !g.Board.Center().Empty() && !touchesAnything(b, m) {
-		return "", fmt.Errorf("move must build off an existing move: %v", m)
+	return u
+}
+
+// UndoMove takes back the move that returned u. Moves must be undone in the
+// reverse order that they were played.
+func (g *Game) UndoMove(u Undo) {
+	g.Board.UndoMove(u.board)
+	g.Bag.Restore(u.bag)
+	g.Round = u.round
+	g.over = u.over
+	g.history = g.history[:u.history]
+	for i, p := range g.Players {
+		p.AddPoints(u.points[i] - p.Points())
+		p.SetRack(u.racks[i])
 	}
+}
 
-	// Perform.
-	points := b.Points(m)
-	player.AddPoints(points)
-	player.UseRack(needed)
-	player.DrawFrom(g.Bag)
-	b.SetAcross(m.Row, m.Col, m.Word)
-	g.Round++
-	return fmt.Sprintf("\n%s scored %d points!\n", player.Name(), points), nil
+// AllMoves returns every move that can be made on the board with the letters
+// of rack, each once, in canonical order. They are generated as configured by
+// g.Gen.
+func (g *Game) AllMoves(rack []Letter) []Move {
+	return collectMoves(g.Dict, g.Board, rack, g.Gen)
 }
 
 func (g *Game) Over() bool {
@@ -112,7 +220,7 @@ func (g *Game) Over() bool {
 		}
 	}
 	for _, p := range g.Players {
-		if len(AllMoves(g.Dict, g.Board, p.Rack())) > 0 {
+		if HasMove(g.Dict, g.Board, p.Rack()) {
 			return false
 		}
 	}
@@ -180,35 +288,19 @@ func (g *Game) String() string {
 	return buf.String()
 }
 
-func (g *Game) CurrentPlayer() Player {
-	return g.Players[g.Round%len(g.Players)]
-}
-
-func makesValidWords(dict *Node, b *board.Board, m Move) bool {
-	t := b.At(m.Row, m.Col)
-	// Valid down.
-	for i, l := range m.Word {
-		l := Letter(l)
-		t := t.RightN(i)
-		if !t.Empty() && t.Letter() != l || !t.InYCrossCheck(l) {
-			return false
-		}
+// Hash returns the Zobrist hash of the position: the letters on the board,
+// each player's rack and whose turn it is. The order of the tiles left in the
+// bag is not part of it.
+func (g *Game) Hash() uint64 {
+	h := g.Board.Hash() ^ zobrist.Seat(g.Round%len(g.Players))
+	for i, p := range g.Players {
+		h ^= zobrist.Mix(i, p.RackHash())
 	}
-	// Valid across.
-	left := t.GatherLeft()
-	right := t.RightN(len(m.Word) - 1).GatherRight()
-	return dict.Search(left + m.Word + right).Accept()
+	return h
 }
 
-func neededFromRack(b *board.Board, m Move) []Letter {
-	t := b.At(m.Row, m.Col)
-	needed := make([]Letter, 0, len(m.Word))
-	for i, l := range m.Word {
-		if t.RightN(i).Empty() {
-			needed = append(needed, Letter(l))
-		}
-	}
-	return needed
+func (g *Game) CurrentPlayer() Player {
+	return g.Players[g.Round%len(g.Players)]
 }
 
 func touchesAnything(b *board.Board, m Move) bool {
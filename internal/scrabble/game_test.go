@@ -12,12 +12,12 @@ func TestGame_AICopy(t *testing.T) {
 	rand.Seed(0)
 	d := dict.NewNode()
 	d.Insert("DO")
-	p1 := NewComputerPlayer("P1", NewLongestStrategy)
-	p2 := NewComputerPlayer("P2", NewLongestStrategy)
+	p1 := NewComputerPlayer("P1", LongestStrategy)
+	p2 := NewComputerPlayer("P2", LongestStrategy)
 	g := NewGame(d, p1, p2)
 	assert.Equal(t, "P1", g.CurrentPlayer().Name())
 	assert.Equal(t, 0, g.Round)
-	g2 := g.AICopy(NewLongestStrategy)
+	g2 := g.AICopy(LongestStrategy)
 	assert.Equal(t, "P1", g2.CurrentPlayer().Name())
 	assert.Equal(t, 0, g2.Round)
 	_, err := g2.playMove(move.Move{
@@ -30,3 +30,142 @@ func TestGame_AICopy(t *testing.T) {
 	assert.Equal(t, 0, g.Round)
 	assert.Equal(t, 1, g2.Round)
 }
+
+func TestGame_UndoMove(t *testing.T) {
+	rand.Seed(0)
+	d := dict.NewNode()
+	d.Insert("DO")
+	d.Insert("DOG")
+	p1 := NewComputerPlayer("P1", LongestStrategy)
+	p2 := NewComputerPlayer("P2", LongestStrategy)
+	g := NewGame(d, p1, p2)
+	p1.SetRack([]dict.Letter("DOGXXXX"))
+	rack := p1.Rack()
+	bag := g.Bag.String()
+
+	u1, err := g.PlayMove(move.Move{Row: 7, Col: 7, Word: "DO"})
+	assert.Nil(t, err)
+	u2, err := g.PlayMove(move.Move{Skip: true})
+	assert.Nil(t, err)
+	p1.SetRack(append(p1.Rack()[:6], 'G'))
+	u3, err := g.PlayMove(move.Move{Row: 7, Col: 7, Word: "DOG"})
+	assert.Nil(t, err)
+	assert.Equal(t, 3, g.Round)
+	assert.Equal(t, 6+5, p1.Points())
+	assert.Equal(t, dict.Letter('G'), g.Board.At(7, 9).Letter())
+	assert.Equal(t, []string{"8H DO", "-", "8H (DO)G"},
+		moveStrings(g.History()))
+
+	g.UndoMove(u3)
+	assert.True(t, g.Board.At(7, 9).Empty())
+	assert.Equal(t, 6, p1.Points())
+	g.UndoMove(u2)
+	assert.Equal(t, 1, g.Round)
+	assert.Equal(t, []string{"8H DO"}, moveStrings(g.History()))
+	g.UndoMove(u1)
+	assert.Equal(t, 0, g.Round)
+	assert.Equal(t, 0, p1.Points())
+	assert.Equal(t, rack, p1.Rack())
+	assert.Equal(t, bag, g.Bag.String())
+	assert.True(t, g.Board.Center().Empty())
+	assert.Equal(t, "P1", g.CurrentPlayer().Name())
+	assert.Empty(t, g.History())
+
+	_, err = g.PlayMove(move.Move{Row: 7, Col: 7, Word: "OD"})
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, g.Round)
+}
+
+func moveStrings(moves []move.Move) []string {
+	ss := make([]string, len(moves))
+	for i, m := range moves {
+		ss[i] = m.String()
+	}
+	return ss
+}
+
+func TestGame_Exchange(t *testing.T) {
+	rand.Seed(0)
+	d := dict.NewNode()
+	p1 := NewComputerPlayer("P1", LongestStrategy)
+	p2 := NewComputerPlayer("P2", LongestStrategy)
+	g := NewGame(d, p1, p2)
+	p1.SetRack([]dict.Letter("QQQ_ABC"))
+	bag := g.Bag.String()
+	n := g.Bag.Len()
+
+	m, err := move.Parse("-QQ?")
+	assert.Nil(t, err)
+	u, err := g.PlayMove(m)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, g.Round)
+	assert.Equal(t, n, g.Bag.Len())
+	assert.Len(t, p1.Rack(), 7)
+	assert.Equal(t, []dict.Letter("QABC"), p1.Rack()[:4])
+	assert.Equal(t, 0, p1.Points())
+
+	g.UndoMove(u)
+	assert.Equal(t, []dict.Letter("QQQ_ABC"), p1.Rack())
+	assert.Equal(t, bag, g.Bag.String())
+
+	_, err = g.PlayMove(move.Move{Skip: true, Exchange: []dict.Letter("QQQQ")})
+	assert.NotNil(t, err)
+	g.Bag.Draw(n - 6)
+	_, err = g.PlayMove(m)
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, g.Round)
+}
+
+func TestGame_Hash(t *testing.T) {
+	rand.Seed(0)
+	d := dict.NewNode()
+	d.Insert("DO")
+	d.Insert("GO")
+	p1 := NewComputerPlayer("P1", LongestStrategy)
+	p2 := NewComputerPlayer("P2", LongestStrategy)
+	g := NewGame(d, p1, p2)
+	p1.SetRack([]dict.Letter("DOGXXXX"))
+	p2.SetRack([]dict.Letter("AAAAAAA"))
+	start := g.Hash()
+
+	// Same racks in swapped seats.
+	p1.SetRack([]dict.Letter("AAAAAAA"))
+	p2.SetRack([]dict.Letter("DOGXXXX"))
+	assert.NotEqual(t, start, g.Hash())
+	p1.SetRack([]dict.Letter("OXXGXXD"))
+	p2.SetRack([]dict.Letter("AAAAAAA"))
+	assert.Equal(t, start, g.Hash())
+
+	u, err := g.PlayMove(move.Move{Row: 7, Col: 7, Word: "DO"})
+	assert.Nil(t, err)
+	assert.NotEqual(t, start, g.Hash())
+	g.UndoMove(u)
+	assert.Equal(t, start, g.Hash())
+
+	_, err = g.PlayMove(move.Move{Skip: true})
+	assert.Nil(t, err)
+	assert.NotEqual(t, start, g.Hash())
+}
+
+func TestGame_Determinize(t *testing.T) {
+	rand.Seed(0)
+	p1 := NewComputerPlayer("P1", LongestStrategy)
+	p2 := NewComputerPlayer("P2", LongestStrategy)
+	g := NewGame(dict.NewNode(), p1, p2)
+	g.Bag.Draw(80)
+	unseen := g.Unseen(0)
+	assert.Equal(t, g.Bag.Len()+len(p2.Rack()), len(unseen))
+	rack2, bag := p2.Rack(), g.Bag.String()
+
+	g2 := g.Determinize(0, LongestStrategy, rand.New(rand.NewSource(1)))
+	assert.Equal(t, p1.Rack(), g2.Players[0].Rack())
+	assert.Equal(t, len(rack2), len(g2.Players[1].Rack()))
+	assert.Equal(t, g.Bag.Len(), g2.Bag.Len())
+	assert.ElementsMatch(t, unseen, g2.Unseen(0))
+	assert.NotEqual(t, bag+string(rack2),
+		g2.Bag.String()+string(g2.Players[1].Rack()))
+
+	// The game itself is left alone.
+	assert.Equal(t, rack2, p2.Rack())
+	assert.Equal(t, bag, g.Bag.String())
+}
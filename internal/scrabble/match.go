@@ -0,0 +1,61 @@
+package scrabble
+
+import (
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+)
+
+// MatchResult is how a strategy did in games against another.
+type MatchResult struct {
+	Wins, Losses, Draws int
+	// Spread is the total of the final spreads.
+	Spread int
+}
+
+// Games returns how many games were played.
+func (r MatchResult) Games() int {
+	return r.Wins + r.Losses + r.Draws
+}
+
+// MatchOptions configures Match.
+type MatchOptions struct {
+	Games int
+	// Endgame, if set, is how both players solve endgames, instead of
+	// DefaultEndgameOptions.
+	Endgame *EndgameOptions
+}
+
+// Match plays opts.Games games between a strategy made by a and one made by
+// b, taking turns going first, and returns how the first did. Each game gets
+// new strategies, so those that keep state between moves start afresh.
+func Match(dict *Node, a, b func() StrategyFunc,
+	opts MatchOptions) MatchResult {
+	var r MatchResult
+	for i := 0; i < opts.Games; i++ {
+		pa, pb := NewComputerPlayer("A", a()), NewComputerPlayer("B", b())
+		if opts.Endgame != nil {
+			pa.SetEndgame(opts.Endgame)
+			pb.SetEndgame(opts.Endgame)
+		}
+		players := []Player{pa, pb}
+		if i%2 == 1 {
+			players[0], players[1] = players[1], players[0]
+		}
+		g := NewGame(dict, players...)
+		for !g.Over() {
+			if _, err := g.PlayRound(); err != nil {
+				panic(err)
+			}
+		}
+		spread := g.WonBy("A")
+		r.Spread += spread
+		switch {
+		case spread > 0:
+			r.Wins++
+		case spread < 0:
+			r.Losses++
+		default:
+			r.Draws++
+		}
+	}
+	return r
+}
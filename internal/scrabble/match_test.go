@@ -0,0 +1,57 @@
+package scrabble
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/leaves"
+	"math/rand"
+	"path/filepath"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	rand.Seed(0)
+	d, err := dict.Load(filepath.Join("..", "..", dict.Dict))
+	if err != nil {
+		panic(err)
+	}
+	mostPoints := func() StrategyFunc { return MostPointsStrategy }
+	random := func() StrategyFunc { return RandomStrategy }
+	r := Match(d, mostPoints, random, MatchOptions{Games: 2,
+		Endgame: &EndgameOptions{MaxDepth: 1}})
+	assert.Equal(t, 2, r.Games())
+	assert.Equal(t, 2, r.Wins)
+	assert.True(t, r.Spread > 0)
+}
+
+// BenchmarkMatch_Widening plays MCTS with progressive widening against MCTS
+// over the ten highest scoring moves, one game per iteration, and reports how
+// the first did. Widening by points measures widening on its own, and by
+// equity together with leave values. Run it with -benchtime=20x or so to get
+// a useful number of games.
+func BenchmarkMatch_Widening(b *testing.B) {
+	d, err := dict.Load(filepath.Join("..", "..", dict.Dict))
+	if err != nil {
+		b.Fatal(err)
+	}
+	fixed := MCTSOptions{Iterations: 20, PickTop: 10, C: 1.4, Reuse: true}
+	for _, bm := range []struct {
+		name  string
+		prior Prior
+	}{
+		{"PointsPrior", PointsPrior},
+		{"EquityPrior", NewEquityPrior(leaves.Heuristic())},
+	} {
+		widening := fixed
+		widening.Prior = bm.prior
+		b.Run(bm.name, func(b *testing.B) {
+			r := Match(d, func() StrategyFunc { return NewMCTS(widening).Play },
+				func() StrategyFunc { return NewMCTS(fixed).Play },
+				MatchOptions{Games: b.N})
+			b.ReportMetric((float64(r.Wins)+float64(r.Draws)/2)/
+				float64(r.Games()), "wins/game")
+			b.ReportMetric(float64(r.Spread)/float64(r.Games()),
+				"spread/game")
+		})
+	}
+}
@@ -1,28 +1,54 @@
 package scrabble
 
 import (
+	"context"
 	"fmt"
 	"github.com/tmazeika/scrabble-go/internal/board"
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/leaves"
 	. "github.com/tmazeika/scrabble-go/internal/move"
+	"github.com/tmazeika/scrabble-go/internal/rules"
 	"math"
+	"math/rand"
+	"runtime"
 	"sort"
 	"strings"
-	"sync"
+	"time"
 )
 
+// MCTSNode is a node of an MCTS tree. Only the goroutine searching the tree
+// touches it; rollouts run on copies of the game and report back.
 type MCTSNode struct {
-	mu *sync.RWMutex
-	wg *sync.WaitGroup
-
 	parent   *MCTSNode
 	children []*MCTSNode
 
-	pickTop int
-	c float64
-	m      Move
-	state  *Game
-	score  int
+	opts *MCTSOptions
+	m    Move
+	// seat is the seat of the player who plays m, and score adds up the
+	// rewards of n's rollouts for that player.
+	seat   int
+	score  float64
 	visits int
+	// avail is how many times n's move could be played when n's parent was
+	// visited. It stands in for the parent's visits in ucb1, since which
+	// moves can be played depends on the tiles dealt to the other players.
+	avail int
+	// pending is how many rollouts through n are still running. Each counts
+	// as a loss for the player at seat in ucb1 until it is done, so that
+	// rollouts running at the same time spread out over the tree.
+	pending int
+	// tried is the moves generated at n for each rack, by its RackHash, that
+	// the player to move has been dealt there, so that they are generated
+	// once for each rack rather than on every visit.
+	tried map[uint64]*triedMoves
+}
+
+// triedMoves is the moves generated at a node for one rack, in the order
+// they are tried. If all is not set, only the first of them were kept, and
+// they are generated again should more be needed.
+type triedMoves struct {
+	moves []Move
+	all   bool
 }
 
 func (n *MCTSNode) String() string {
@@ -31,47 +57,68 @@ func (n *MCTSNode) String() string {
 
 func (n *MCTSNode) string(indent int) string {
 	str := strings.Repeat("  ", indent) +
-		fmt.Sprintf("(%d/%d) -- %s\n", n.score, n.visits, n.m)
+		fmt.Sprintf("(%.4g/%d) -- %s\n", n.score, n.visits, n.m)
 	for _, c := range n.children {
 		str += c.string(indent + 1)
 	}
 	return str
 }
 
-func (n *MCTSNode) search(playerName string, release <-chan struct{}) {
-	n.mu.RLock()
+// search selects and expands the leaf to roll out from for one iteration
+// from n, which must be the root of the tree, on state, a deal of the tiles
+// that the player searching cannot see. It plays the moves down to the leaf
+// on state, using only those that can be played with the racks dealt, and
+// marks the rollout pending on the way. rootMoves are the moves at the root,
+// for progressive widening. It returns the leaf and what takes back the
+// moves played, in the order they were played.
+func (n *MCTSNode) search(state *Game, rootMoves []Move) (*MCTSNode,
+	[]Undo) {
 	leaf := n
-	for len(leaf.children) > 0 {
-		leaf = leaf.selectChild()
-	}
-	if leaf.visits > 0 {
-		leaf.expand()
-		leaf = leaf.selectChild()
-	}
-	state := leaf.state.AICopy(MostPointsStrategy)
-	n.mu.RUnlock()
-	leaf.wg.Add(1)
-	go func() {
-		defer leaf.wg.Done()
-		score := rollout(state, playerName)
-		leaf.mu.Lock()
-		leaf.backPropagate(score)
-		leaf.mu.Unlock()
-		<-release
-	}()
+	var undos []Undo
+	for !state.Over() {
+		switch {
+		case n.opts.Prior != nil && leaf == n:
+			leaf.widen(state, rootMoves)
+		case n.opts.Prior != nil && leaf.visits > 0:
+			leaf.widen(state, nil)
+		case leaf != n && leaf.visits > 0:
+			leaf.expand(state)
+		}
+		child := leaf.selectChild(state)
+		if child == nil {
+			break
+		}
+		undos = append(undos, child.play(state))
+		leaf = child
+		if leaf.visits == 0 {
+			break
+		}
+	}
+	for n2 := leaf; n2 != nil; n2 = n2.parent {
+		n2.pending++
+	}
+	return leaf, undos
 }
 
-func (n *MCTSNode) selectChild() *MCTSNode {
-	if len(n.children) == 0 {
-		return n
+func (n *MCTSNode) play(state *Game) Undo {
+	u, err := state.PlayMove(n.m)
+	if err != nil {
+		panic(err)
 	}
+	return u
+}
+
+// selectChild returns the child of n with the highest ucb1 among those that
+// can be played on state, or nil if there are none.
+func (n *MCTSNode) selectChild(state *Game) *MCTSNode {
 	var best *MCTSNode
 	var bestUCB1 float64
 	for _, c := range n.children {
-		ucb1 := c.ucb1()
-		if math.IsInf(ucb1, 1) {
-			return c
+		if _, err := state.Validate(c.m); err != nil {
+			continue
 		}
+		c.avail++
+		ucb1 := c.ucb1()
 		if best == nil || ucb1 > bestUCB1 {
 			best, bestUCB1 = c, ucb1
 		}
@@ -80,63 +127,220 @@ func (n *MCTSNode) selectChild() *MCTSNode {
 }
 
 func (n *MCTSNode) ucb1() float64 {
-	if n.visits == 0 {
+	if n.visits+n.pending == 0 {
 		return math.Inf(1)
 	}
-	scoref := float64(n.score)
-	visitsf := float64(n.visits)
-	parentVisitsf := float64(n.parent.visits)
-	return scoref/visitsf +
-		n.c*math.Sqrt(math.Log(parentVisitsf)/visitsf)
+	scoref := n.score - float64(n.pending)
+	visitsf := float64(n.visits + n.pending)
+	availf := float64(n.avail)
+	return scoref/visitsf + n.opts.C*math.Sqrt(math.Log(availf)/visitsf)
+}
+
+// expand adds the top moves that can be played on state as children of n,
+// unless it already has for the current player's rack.
+func (n *MCTSNode) expand(state *Game) {
+	key := state.CurrentPlayer().RackHash()
+	if n.tried[key] != nil {
+		return
+	}
+	moves, _ := TopMoves(context.Background(), state.Dict, state.Board,
+		state.CurrentPlayer().Rack(), n.opts.PickTop, state.Gen,
+		state.Board.Points)
+	n.try(key, &triedMoves{moves: moves, all: true})
+	n.addChildren(state, moves)
+}
+
+func (n *MCTSNode) try(key uint64, t *triedMoves) {
+	if n.tried == nil {
+		n.tried = make(map[uint64]*triedMoves)
+	}
+	n.tried[key] = t
+}
+
+// widen adds the moves that can be played on state, in order of the prior,
+// as children of n until as many of its children can be played as its
+// visits allow. If moves is nil, the moves are generated along with passing
+// and any exchanges. The order is kept for the current player's rack, far
+// enough ahead to widen n a few more times.
+func (n *MCTSNode) widen(state *Game, moves []Move) {
+	width := n.opts.width(n.visits)
+	legal := 0
+	for _, c := range n.children {
+		if _, err := state.Validate(c.m); err == nil {
+			legal++
+		}
+	}
+	if legal >= width {
+		return
+	}
+	key := state.CurrentPlayer().RackHash()
+	tried := n.tried[key]
+	if tried == nil || !tried.all && len(tried.moves) < width {
+		tried = n.byPrior(state, moves, 2*width)
+		n.try(key, tried)
+	}
+	for _, m := range tried.moves {
+		if legal >= width {
+			break
+		}
+		if n.child(m) == nil {
+			n.addChildren(state, []Move{m})
+			legal++
+		}
+	}
 }
 
-func (n *MCTSNode) expand() {
-	n.expandExisting(getTopMoves(n.state.Board,
-		AllMoves(n.state.Dict, n.state.Board, n.state.CurrentPlayer().Rack()),
-		n.pickTop))
+// byPrior returns the first keep of moves in order of the prior, generating
+// them as widen does if moves is nil.
+func (n *MCTSNode) byPrior(state *Game, moves []Move,
+	keep int) *triedMoves {
+	if moves == nil {
+		moves = state.AllMoves(state.CurrentPlayer().Rack())
+	}
+	moves = append(moves[:len(moves):len(moves)], Move{Skip: true})
+	if state.Bag.Len() >= rules.RackSize {
+		moves = append(moves, exchanges(state.CurrentPlayer().Rack())...)
+	}
+	prior := make([]float64, len(moves))
+	for i, m := range moves {
+		prior[i] = n.opts.Prior(state, m)
+	}
+	order := make([]int, len(moves))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return prior[order[i]] > prior[order[j]]
+	})
+	t := &triedMoves{all: len(order) <= keep}
+	if !t.all {
+		order = order[:keep]
+	}
+	t.moves = make([]Move, len(order))
+	for j, i := range order {
+		t.moves[j] = moves[i]
+	}
+	return t
 }
 
-func (n *MCTSNode) expandExisting(moves []Move) {
+// exchanges returns every different exchange of some of the letters of
+// rack.
+func exchanges(rack []Letter) []Move {
+	seen := make(map[string]bool)
+	var moves []Move
+	for mask := 1; mask < 1<<len(rack); mask++ {
+		var ls []Letter
+		for i, l := range rack {
+			if mask&(1<<i) != 0 {
+				ls = append(ls, l)
+			}
+		}
+		sort.Slice(ls, func(i, j int) bool {
+			return ls[i] < ls[j]
+		})
+		if !seen[string(ls)] {
+			seen[string(ls)] = true
+			moves = append(moves, Move{Skip: true, Exchange: ls})
+		}
+	}
+	return moves
+}
+
+// addChildren adds moves of the current player of state as children of n,
+// apart from those it already has.
+func (n *MCTSNode) addChildren(state *Game, moves []Move) {
+	seat := state.Round % len(state.Players)
 	for _, m := range moves {
-		child := MCTSNode{
-			mu:     n.mu,
-			wg:     n.wg,
+		if n.child(m) != nil {
+			continue
+		}
+		n.children = append(n.children, &MCTSNode{
 			parent: n,
+			opts:   n.opts,
 			m:      m,
-			state:  n.state.AICopy(nil),
-		}
-		if _, err := child.state.playMove(m); err != nil {
-			panic(err)
+			seat:   seat,
+		})
+	}
+}
+
+// child returns the child of n for m, or nil if there is none.
+func (n *MCTSNode) child(m Move) *MCTSNode {
+	key := m.String()
+	for _, c := range n.children {
+		if c.m.String() == key {
+			return c
 		}
-		n.children = append(n.children, &child)
 	}
+	return nil
 }
 
-func rollout(state *Game, playerName string) int {
-	for !state.Over() {
-		if _, err := state.PlayRound(); err != nil {
+// rollout plays state on by the players' strategies for up to Plies turns,
+// or to the end of the game, and returns the reward for playerName. The
+// moves are taken back before it returns. It may run on many goroutines at
+// once.
+func (o *MCTSOptions) rollout(state *Game, playerName string) float64 {
+	var undos []Undo
+	defer func() {
+		for i := len(undos) - 1; i >= 0; i-- {
+			state.UndoMove(undos[i])
+		}
+	}()
+	for i := 0; !state.Over(); i++ {
+		if o.Plies > 0 && i >= o.Plies {
+			return o.reward(o.Evaluator(state, playerName), false)
+		}
+		u, err := state.PlayMove(state.CurrentPlayer().Play(state))
+		if err != nil {
 			panic(err)
 		}
+		undos = append(undos, u)
+	}
+	return o.reward(float64(state.WonBy(playerName)), true)
+}
+
+// reward turns v, how many points the player is ahead by, into a reward
+// between -1 and 1. over is set if the game has ended.
+func (o *MCTSOptions) reward(v float64, over bool) float64 {
+	scale := o.Scale
+	if scale <= 0 {
+		scale = 50
 	}
-	if wonBy := state.WonBy(playerName); wonBy < 0 {
-		return -1
-	} else if wonBy == 0 {
-		return 0
+	var win float64
+	switch {
+	case !over:
+		// 2p-1 for the chance p = 1/(1+e^(-v/scale)) of winning.
+		win = math.Tanh(v / scale / 2)
+	case v > 0:
+		win = 1
+	case v < 0:
+		win = -1
 	}
-	return 1
+	return (1-o.SpreadWeight)*win + o.SpreadWeight*math.Tanh(v/scale)
 }
 
-func (n *MCTSNode) backPropagate(score int) {
+// backPropagate adds the result of a pending rollout from n, where score is
+// the reward for the player at seat. The other players count it as the
+// opposite.
+func (n *MCTSNode) backPropagate(seat int, score float64) {
 	for n2 := n; n2 != nil; n2 = n2.parent {
-		n2.score += score
+		if n2.seat == seat {
+			n2.score += score
+		} else {
+			n2.score -= score
+		}
 		n2.visits++
+		n2.pending--
 	}
 }
 
-func (n *MCTSNode) bestChild() *MCTSNode {
+// bestChild returns the most visited child of n that can be played on game.
+func (n *MCTSNode) bestChild(game *Game) *MCTSNode {
 	var best *MCTSNode
 	var bestVisits int
 	for _, c := range n.children {
+		if _, err := game.Validate(c.m); err != nil {
+			continue
+		}
 		if best == nil || c.visits > bestVisits {
 			best, bestVisits = c, c.visits
 		}
@@ -144,25 +348,244 @@ func (n *MCTSNode) bestChild() *MCTSNode {
 	return best
 }
 
-func mcts(state *Game, moves []Move, iterations, pickTop int, c float64) Move {
+// MCTSOptions configures an MCTS.
+type MCTSOptions struct {
+	// Iterations is the most iterations run for a move and Budget the most
+	// time spent on one. Zero means no limit, but with neither set one
+	// iteration is run.
+	Iterations int
+	Budget     time.Duration
+	// PickTop is how many of the highest scoring moves are tried in each
+	// position, unless there is a Prior.
+	PickTop int
+	// Prior, if set, turns on progressive widening: a position's moves,
+	// passing and exchanges included, are tried in order of Prior, and the
+	// more a position is visited the more of them are, up to
+	// ceil(Widen * (visits+1)^WidenExp). Widen and WidenExp default to 1
+	// and 0.5.
+	Prior    Prior
+	Widen    float64
+	WidenExp float64
+	// C is the exploration constant of UCB1.
+	C float64
+	// Rollout is the strategy that every player follows in rollouts. Nil
+	// means MostPointsStrategy. It and Evaluator are called from as many
+	// goroutines at once as Parallelism allows.
+	Rollout StrategyFunc
+	// Plies, if set, cuts rollouts short after that many turns, and the
+	// position is then valued by Evaluator, which defaults to
+	// NewStaticEvaluator with leaves.Heuristic.
+	Plies     int
+	Evaluator Evaluator
+	// A rollout is rewarded with 1 for a win, -1 for a loss and 0 for a
+	// draw, or when cut short with 2p-1, where p = 1/(1+e^(-v/Scale)) is the
+	// chance of winning estimated from the Evaluator's value v. SpreadWeight,
+	// from 0 to 1, mixes that with tanh(spread/Scale) instead, to prefer
+	// winning by more. Scale defaults to 50 points.
+	SpreadWeight float64
+	Scale        float64
+	// Parallelism is the most rollouts run at once. Zero means
+	// runtime.GOMAXPROCS(0).
+	Parallelism int
+	// Seed, if set, seeds the deals that are searched. With Iterations
+	// rather than Budget, the same Parallelism and a Rollout that does not
+	// use the global source, the same searches then give the same moves.
+	Seed int64
+	// Reuse keeps the tree below the move played and the replies to it, to
+	// carry on searching from on the next move.
+	Reuse bool
+}
+
+func (o *MCTSOptions) width(visits int) int {
+	widen, exp := o.Widen, o.WidenExp
+	if widen <= 0 {
+		widen = 1
+	}
+	if exp <= 0 {
+		exp = 0.5
+	}
+	return int(math.Ceil(widen * math.Pow(float64(visits+1), exp)))
+}
+
+// Prior rates a move of the current player of game for MCTS to try, higher
+// first.
+type Prior func(game *Game, m Move) float64
+
+// PointsPrior rates moves by their points.
+func PointsPrior(game *Game, m Move) float64 {
+	return float64(game.Board.Points(m))
+}
+
+// NewEquityPrior returns a Prior that rates moves by their Equity.
+func NewEquityPrior(table *leaves.Table) Prior {
+	return func(game *Game, m Move) float64 {
+		return Equity(game, game.CurrentPlayer().Rack(), m, table)
+	}
+}
+
+// Evaluator returns how many points the player called name is worth to be
+// ahead by in game, which may not be over.
+type Evaluator func(game *Game, name string) float64
+
+// NewStaticEvaluator returns an Evaluator that values a position by how far
+// ahead the player is in points, plus the value in table of their rack while
+// there are tiles left to draw.
+func NewStaticEvaluator(table *leaves.Table) Evaluator {
+	return func(game *Game, name string) float64 {
+		v := float64(game.WonBy(name))
+		if !game.Bag.Empty() {
+			for _, p := range game.Players {
+				if p.Name() == name {
+					v += table.Value(p.Rack())
+				}
+			}
+		}
+		return v
+	}
+}
+
+// MCTS chooses moves by Monte Carlo tree search. It is not safe for
+// concurrent use.
+type MCTS struct {
+	opts MCTSOptions
+	rand *rand.Rand
+	root *MCTSNode
+	// game, seat and history are the game that root was searched in, the
+	// seat it was searched for and the moves played before it.
+	game    *Game
+	seat    int
+	history []Move
+}
+
+func NewMCTS(opts MCTSOptions) *MCTS {
+	if opts.Rollout == nil {
+		opts.Rollout = MostPointsStrategy
+	}
+	if opts.Plies > 0 && opts.Evaluator == nil {
+		opts.Evaluator = NewStaticEvaluator(leaves.Heuristic())
+	}
+	seed := opts.Seed
+	if seed == 0 {
+		seed = rand.Int63()
+	}
+	return &MCTS{opts: opts, rand: rand.New(rand.NewSource(seed))}
+}
+
+// Play is a StrategyFunc that searches for the best of moves.
+func (t *MCTS) Play(game *Game, moves []Move) Move {
+	if len(moves) == 0 {
+		return Move{Skip: true}
+	}
+	root := t.search(game, moves, t.rand)
+	return root.bestChild(game).m
+}
+
+// search searches from state and returns the root of the tree. Every
+// iteration searches a new deal, made by r, of the tiles that state's
+// current player cannot see, so the search knows no more than the player
+// does. Rollouts run on their own goroutines, but their results are added to
+// the tree in the order they were started, so that the search does not
+// depend on which finishes first. Each of the Parallelism rollouts that can
+// run at once has a copy of state of its own, which is dealt again and has
+// its moves taken back for the next rollout to use.
+func (t *MCTS) search(state *Game, moves []Move, r *rand.Rand) *MCTSNode {
+	root := t.reuse(state)
+	if root == nil {
+		// The root's move is the one before, by the player before.
+		root = &MCTSNode{
+			opts: &t.opts,
+			seat: (state.Round + len(state.Players) - 1) % len(state.Players),
+		}
+	}
+	if t.opts.Prior == nil {
+		root.addChildren(state, getTopMoves(state.Board, moves, t.opts.PickTop))
+	}
+	seat := state.Round % len(state.Players)
 	playerName := state.CurrentPlayer().Name()
-	root := MCTSNode{
-		mu:    &sync.RWMutex{},
-		wg:    &sync.WaitGroup{},
-		pickTop: pickTop,
-		c: c,
-		state: state.AICopy(nil),
-	}
-	root.expandExisting(getTopMoves(state.Board, moves, pickTop))
-	sem := make(chan struct{}, 2)
-	i := 0
-	for ; i < iterations; i++ {
-		sem <- struct{}{}
-		root.search(playerName, sem)
-	}
-	root.wg.Wait()
-	// fmt.Printf("MCTS stats: %d rollouts completed\n", i)
-	return root.bestChild().m
+	parallelism := t.opts.Parallelism
+	if parallelism <= 0 {
+		parallelism = runtime.GOMAXPROCS(0)
+	}
+	states := make([]*Game, parallelism)
+	unseen := state.Unseen(seat)
+	type rollout struct {
+		leaf  *MCTSNode
+		state *Game
+		undos []Undo
+		score chan float64
+	}
+	var running []rollout
+	finish := func() {
+		r := running[0]
+		running = running[1:]
+		r.leaf.backPropagate(seat, <-r.score)
+		for i := len(r.undos) - 1; i >= 0; i-- {
+			r.state.UndoMove(r.undos[i])
+		}
+	}
+	start := time.Now()
+	for i := 0; t.opts.Iterations <= 0 || i < t.opts.Iterations; i++ {
+		if i > 0 && (t.opts.Budget > 0 && time.Since(start) >= t.opts.Budget ||
+			t.opts.Budget <= 0 && t.opts.Iterations <= 0) {
+			break
+		}
+		if len(running) == parallelism {
+			finish()
+		}
+		// The rollout that last used this copy was the first running, and
+		// has been finished.
+		s := states[i%parallelism]
+		if s == nil {
+			s = state.AICopy(t.opts.Rollout)
+			// Rollouts already run side by side, so their moves are
+			// generated on the rollout's own goroutine unless the game has
+			// a pool to share.
+			s.Gen = GenOptions{Pool: state.Gen.Pool, Parallelism: 1}
+			states[i%parallelism] = s
+		}
+		s.deal(seat, unseen, r)
+		leaf, undos := root.search(s, moves)
+		score := make(chan float64, 1)
+		go func() {
+			score <- t.opts.rollout(s, playerName)
+		}()
+		running = append(running, rollout{leaf, s, undos, score})
+	}
+	for len(running) > 0 {
+		finish()
+	}
+	if t.opts.Reuse {
+		t.root, t.game, t.seat = root, state, seat
+		t.history = state.History()
+	}
+	return root
+}
+
+// reuse returns the node of the last tree that the moves played since lead
+// to, as the root of a new tree, if state is the same game, searched for
+// the same seat, and has gone on from where the last tree was searched.
+func (t *MCTS) reuse(state *Game) *MCTSNode {
+	if t.root == nil || state != t.game ||
+		state.Round%len(state.Players) != t.seat {
+		return nil
+	}
+	history := state.History()
+	if len(history) <= len(t.history) {
+		return nil
+	}
+	for i, m := range t.history {
+		if history[i].String() != m.String() {
+			return nil
+		}
+	}
+	node := t.root
+	for _, m := range history[len(t.history):] {
+		if node = node.child(m); node == nil {
+			return nil
+		}
+	}
+	node.parent = nil
+	return node
 }
 
 func getTopMoves(b *board.Board, m []Move, pickTop int) []Move {
@@ -0,0 +1,273 @@
+package scrabble
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/leaves"
+	"github.com/tmazeika/scrabble-go/internal/move"
+	"math"
+	"math/rand"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func mctsFixture() *Game {
+	rand.Seed(0)
+	d, err := dict.Load(filepath.Join("..", "..", dict.Dict))
+	if err != nil {
+		panic(err)
+	}
+	g := NewGame(d, NewComputerPlayer("P1", MostPointsStrategy),
+		NewComputerPlayer("P2", MostPointsStrategy))
+	g.Gen = GenOptions{Parallelism: 1}
+	for i := 0; i < 6; i++ {
+		if _, err := g.PlayRound(); err != nil {
+			panic(err)
+		}
+	}
+	g.Bag.Draw(g.Bag.Len() - 4)
+	return g
+}
+
+func TestMCTS_Honest(t *testing.T) {
+	g := mctsFixture()
+	// The same position, with the tiles P1 cannot see dealt differently.
+	g2 := g.Determinize(0, MostPointsStrategy, rand.New(rand.NewSource(1)))
+	assert.NotEqual(t, g.Players[1].Rack(), g2.Players[1].Rack())
+
+	moves := g.AllMoves(g.CurrentPlayer().Rack())
+	opts := MCTSOptions{Iterations: 30, PickTop: 3, C: 1.4, Parallelism: 1}
+	tree := NewMCTS(opts).search(g, moves, rand.New(rand.NewSource(2)))
+	tree2 := NewMCTS(opts).search(g2, moves, rand.New(rand.NewSource(2)))
+	assert.Equal(t, 30, tree.visits)
+	assert.Equal(t, tree.String(), tree2.String())
+
+	// The opponent's replies come from more than one deal.
+	replies := 0
+	for _, c := range tree.children {
+		replies += len(c.children)
+	}
+	assert.True(t, replies > len(tree.children)*3)
+}
+
+func TestMCTS_Reuse(t *testing.T) {
+	g := mctsFixture()
+	opts := MCTSOptions{Iterations: 20, PickTop: 3, C: 1.4, Parallelism: 1,
+		Reuse: true}
+	mcts, other := NewMCTS(opts), NewMCTS(opts)
+	moves := g.AllMoves(g.CurrentPlayer().Rack())
+	root := mcts.search(g, moves, rand.New(rand.NewSource(1)))
+	other.search(g, moves, rand.New(rand.NewSource(1)))
+	g2 := g.AICopy(MostPointsStrategy)
+	played := root.bestChild(g)
+	_, err := g.PlayMove(played.m)
+	assert.Nil(t, err)
+	var reply *MCTSNode
+	for _, c := range played.children {
+		if _, err := g.Validate(c.m); err == nil {
+			reply = c
+			break
+		}
+	}
+	if reply == nil {
+		t.Fatal("no reply in the tree can be played")
+	}
+	_, err = g.PlayMove(reply.m)
+	assert.Nil(t, err)
+	for _, m := range g.History()[len(g2.History()):] {
+		_, err = g2.PlayMove(m)
+		assert.Nil(t, err)
+	}
+
+	// The search carries on from the reply.
+	visits := reply.visits
+	root = mcts.search(g, g.AllMoves(g.CurrentPlayer().Rack()),
+		rand.New(rand.NewSource(2)))
+	assert.True(t, root == reply)
+	assert.Nil(t, root.parent)
+	assert.Equal(t, visits+20, root.visits)
+
+	// Another game that gets to the same position, or the other player's
+	// turn in this one, starts a new tree.
+	root = other.search(g2, g2.AllMoves(g2.CurrentPlayer().Rack()),
+		rand.New(rand.NewSource(2)))
+	assert.Equal(t, 20, root.visits)
+	u, err := g.PlayMove(mcts.root.bestChild(g).m)
+	assert.Nil(t, err)
+	root = mcts.search(g, g.AllMoves(g.CurrentPlayer().Rack()),
+		rand.New(rand.NewSource(3)))
+	assert.Equal(t, 20, root.visits)
+	g.UndoMove(u)
+
+	// A move the tree does not know starts a new one.
+	_, err = g.PlayMove(move.Move{Skip: true})
+	assert.Nil(t, err)
+	_, err = g.PlayMove(move.Move{Skip: true})
+	assert.Nil(t, err)
+	root = mcts.search(g, g.AllMoves(g.CurrentPlayer().Rack()),
+		rand.New(rand.NewSource(3)))
+	assert.Equal(t, 20, root.visits)
+}
+
+func TestMCTS_Budget(t *testing.T) {
+	g := mctsFixture()
+	mcts := NewMCTS(MCTSOptions{Budget: 100 * time.Millisecond, PickTop: 3,
+		C: 1.4, Parallelism: 2})
+	start := time.Now()
+	m := mcts.Play(g, g.AllMoves(g.CurrentPlayer().Rack()))
+	assert.True(t, time.Since(start) >= 100*time.Millisecond)
+	_, err := g.Validate(m)
+	assert.Nil(t, err)
+}
+
+func TestMCTS_Widening(t *testing.T) {
+	g := mctsFixture()
+	moves := g.AllMoves(g.CurrentPlayer().Rack())
+	root := NewMCTS(MCTSOptions{Iterations: 30, C: 1.4, Parallelism: 1,
+		Prior: PointsPrior}).search(g, moves, rand.New(rand.NewSource(1)))
+	assert.Equal(t, 30, root.visits)
+	assert.True(t, len(root.children) > 1)
+	assert.True(t, len(root.children) <= root.opts.width(30))
+	best := MostPointsStrategy(g, moves)
+	assert.Equal(t, best.String(), root.children[0].m.String())
+
+	// Moves that score nothing are tried if the prior likes them.
+	passFirst := func(game *Game, m move.Move) float64 {
+		if m.Skip && len(m.Exchange) == 0 {
+			return 1
+		}
+		return 0
+	}
+	root = NewMCTS(MCTSOptions{Iterations: 5, C: 1.4, Parallelism: 1,
+		Prior: passFirst}).search(g, moves, rand.New(rand.NewSource(1)))
+	assert.Equal(t, move.Move{Skip: true}, root.children[0].m)
+}
+
+func TestMCTSNode_Widen_Tried(t *testing.T) {
+	// The moves are put in order of the prior once for a rack, and again
+	// only when widening needs more of them than were kept.
+	g := mctsFixture()
+	calls := 0
+	prior := func(game *Game, m move.Move) float64 {
+		calls++
+		return PointsPrior(game, m)
+	}
+	n := &MCTSNode{opts: &MCTSOptions{Prior: prior}}
+	n.widen(g, nil)
+	assert.Len(t, n.children, 1)
+	generated := calls
+	assert.True(t, generated > 0)
+	n.visits = 3
+	n.widen(g, nil)
+	assert.Len(t, n.children, 2)
+	assert.Equal(t, generated, calls)
+	n.visits = 8
+	n.widen(g, nil)
+	assert.Len(t, n.children, 3)
+	assert.Equal(t, 2*generated, calls)
+}
+
+func TestExchanges(t *testing.T) {
+	var ss []string
+	for _, m := range exchanges([]dict.Letter("ABA")) {
+		ss = append(ss, m.String())
+	}
+	assert.ElementsMatch(t, []string{"-A", "-B", "-AA", "-AB", "-AAB"}, ss)
+}
+
+func TestMCTS_Truncated(t *testing.T) {
+	g := mctsFixture()
+	var turns, evals int64
+	opts := MCTSOptions{Iterations: 20, PickTop: 3, C: 1.4, Parallelism: 1,
+		Rollout: func(game *Game, moves []move.Move) move.Move {
+			atomic.AddInt64(&turns, 1)
+			return RandomStrategy(game, moves)
+		},
+		Plies: 2,
+		Evaluator: func(game *Game, name string) float64 {
+			atomic.AddInt64(&evals, 1)
+			assert.Equal(t, "P1", name)
+			return 1000
+		},
+	}
+	root := NewMCTS(opts).search(g, g.AllMoves(g.CurrentPlayer().Rack()),
+		rand.New(rand.NewSource(1)))
+	assert.Equal(t, 20, root.visits)
+	assert.True(t, evals > 0)
+	assert.True(t, turns <= 2*20)
+	// Every rollout cut short was rewarded as all but won. The root is
+	// scored for P2, who played before.
+	assert.True(t, -root.score > float64(evals)*0.99-float64(20-evals))
+}
+
+func TestMCTSOptions_Reward(t *testing.T) {
+	o := MCTSOptions{}
+	assert.Equal(t, 1.0, o.reward(1, true))
+	assert.Equal(t, 0.0, o.reward(0, true))
+	assert.Equal(t, -1.0, o.reward(-200, true))
+	assert.Equal(t, 0.0, o.reward(0, false))
+	assert.InDelta(t, 2/(1+math.Exp(-1))-1, o.reward(50, false), 1e-9)
+	assert.InDelta(t, -o.reward(50, false), o.reward(-50, false), 1e-9)
+
+	o = MCTSOptions{SpreadWeight: 0.5, Scale: 100}
+	assert.InDelta(t, 0.5+0.5*math.Tanh(0.1), o.reward(10, true), 1e-9)
+	assert.True(t, o.reward(100, true) > o.reward(10, true))
+}
+
+func TestStaticEvaluator(t *testing.T) {
+	g := mctsFixture()
+	eval := NewStaticEvaluator(leaves.Heuristic())
+	leave := leaves.Heuristic().Value(g.Players[1].Rack())
+	assert.Equal(t, float64(g.WonBy("P2"))+leave, eval(g, "P2"))
+	g.Bag.Draw(g.Bag.Len())
+	assert.Equal(t, float64(g.WonBy("P2")), eval(g, "P2"))
+}
+
+func TestMCTS_VirtualLoss(t *testing.T) {
+	g := mctsFixture()
+	// Rollouts started together go down different moves.
+	root := NewMCTS(MCTSOptions{Iterations: 3, PickTop: 3, C: 1.4,
+		Parallelism: 3}).search(g, g.AllMoves(g.CurrentPlayer().Rack()),
+		rand.New(rand.NewSource(1)))
+	assert.Len(t, root.children, 3)
+	for _, c := range root.children {
+		assert.Equal(t, 1, c.visits)
+		assert.Equal(t, 0, c.pending)
+	}
+}
+
+func TestMCTS_Seed(t *testing.T) {
+	g := mctsFixture()
+	moves := g.AllMoves(g.CurrentPlayer().Rack())
+	for _, opts := range []MCTSOptions{
+		{Iterations: 20, PickTop: 3, C: 1.4, Parallelism: 1, Seed: 1},
+		{Iterations: 20, PickTop: 3, C: 1.4, Parallelism: 4, Seed: 1},
+		{Iterations: 20, C: 1.4, Parallelism: 4, Seed: 1, Plies: 2,
+			Prior: PointsPrior},
+	} {
+		mcts, mcts2 := NewMCTS(opts), NewMCTS(opts)
+		tree := mcts.search(g, moves, mcts.rand)
+		tree2 := mcts2.search(g, moves, mcts2.rand)
+		assert.Equal(t, 20, tree.visits)
+		assert.Equal(t, tree.String(), tree2.String())
+	}
+}
+
+func TestMCTS_Opponent(t *testing.T) {
+	// P1 can only pass. P2 then wins by going out with ZEA, and loses with
+	// ZA, after which P1 goes out with S.
+	g := endgameFixture("S", "ZE")
+	g.Players[0].AddPoints(10)
+	pass := move.Move{Skip: true}
+	root := NewMCTS(MCTSOptions{Iterations: 60, PickTop: 3, C: 1.4,
+		Parallelism: 1}).search(g, []move.Move{pass},
+		rand.New(rand.NewSource(1)))
+	assert.Len(t, root.children, 1)
+	replies := root.children[0]
+	_, err := g.PlayMove(pass)
+	assert.Nil(t, err)
+	assert.Equal(t, "H6 ZE(A)", replies.bestChild(g).m.String())
+	assert.True(t, replies.score < 0)
+}
@@ -0,0 +1,505 @@
+package scrabble
+
+import (
+	"container/heap"
+	"context"
+	"github.com/tmazeika/scrabble-go/internal/board"
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+	. "github.com/tmazeika/scrabble-go/internal/move"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// GenOptions configures move generation.
+type GenOptions struct {
+	// Pool, if set, is where moves are generated, and Parallelism is
+	// ignored.
+	Pool *Pool
+	// Parallelism is the most goroutines that generate moves at once. Zero
+	// means runtime.GOMAXPROCS(0), and one generates moves on the calling
+	// goroutine.
+	Parallelism int
+}
+
+func (o GenOptions) parallelism() int {
+	if o.Parallelism <= 0 {
+		return runtime.GOMAXPROCS(0)
+	}
+	return o.Parallelism
+}
+
+// Pool is a fixed set of goroutines, each with its own scratch buffers, that
+// any number of GenerateMoves calls can share. It bounds the goroutines used
+// for move generation however many searches run at once.
+type Pool struct {
+	jobs chan poolJob
+	wg   sync.WaitGroup
+}
+
+type poolJob struct {
+	gen  generator
+	rack []Letter
+	done *sync.WaitGroup
+}
+
+// NewPool starts a pool of workers goroutines. It must be closed when no
+// longer needed.
+func NewPool(workers int) *Pool {
+	if workers < 1 {
+		panic("nonpositive worker count")
+	}
+	p := &Pool{jobs: make(chan poolJob)}
+	p.wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer p.wg.Done()
+			var s scratch
+			for j := range p.jobs {
+				j.gen.run(&s, j.rack)
+				j.done.Done()
+			}
+		}()
+	}
+	return p
+}
+
+// Close stops the workers once they finish their current jobs. GenerateMoves
+// must not be called with the pool afterwards.
+func (p *Pool) Close() {
+	close(p.jobs)
+	p.wg.Wait()
+}
+
+// GenerateMoves calls fn with each move that can be made on b with the
+// letters of rack. Calls to fn never overlap. Generation stops early when fn
+// returns false or when ctx is done, in which case ctx.Err() is returned.
+func GenerateMoves(ctx context.Context, dict *Node, b *board.Board,
+	rack []Letter, opts GenOptions, fn func(Move) bool) error {
+	b.SetYCrossChecks(dict)
+	bt := b.Transposed()
+	bt.SetYCrossChecks(dict)
+	jobs := anchorJobs(b, false)
+	jobs = append(jobs, anchorJobs(bt, true)...)
+
+	var stop int32
+	// Contexts that can never be done, like context.Background, need no
+	// watching.
+	if ctx.Done() != nil {
+		watch, cancel := context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			<-watch.Done()
+			atomic.StoreInt32(&stop, 1)
+		}()
+	}
+	parallel := opts.Pool != nil || opts.parallelism() > 1
+	var mu sync.Mutex
+	emit := func(m Move) {
+		if parallel {
+			mu.Lock()
+			defer mu.Unlock()
+		}
+		if atomic.LoadInt32(&stop) != 0 {
+			return
+		}
+		if ctx.Err() != nil || !fn(m) {
+			atomic.StoreInt32(&stop, 1)
+		}
+	}
+	newGenerator := func(j anchorJob) generator {
+		return generator{
+			dict:       dict,
+			anchor:     j.anchor,
+			transposed: j.transposed,
+			stop:       &stop,
+			emit:       emit,
+		}
+	}
+
+	switch {
+	case opts.Pool != nil:
+		var done sync.WaitGroup
+		for _, j := range jobs {
+			if atomic.LoadInt32(&stop) != 0 {
+				break
+			}
+			done.Add(1)
+			opts.Pool.jobs <- poolJob{newGenerator(j), rack, &done}
+		}
+		done.Wait()
+	case !parallel:
+		var s scratch
+		for _, j := range jobs {
+			if atomic.LoadInt32(&stop) != 0 {
+				break
+			}
+			g := newGenerator(j)
+			g.run(&s, rack)
+		}
+	default:
+		in := make(chan anchorJob)
+		var wg sync.WaitGroup
+		n := opts.parallelism()
+		wg.Add(n)
+		for i := 0; i < n; i++ {
+			go func() {
+				defer wg.Done()
+				var s scratch
+				for j := range in {
+					g := newGenerator(j)
+					g.run(&s, rack)
+				}
+			}()
+		}
+		for _, j := range jobs {
+			if atomic.LoadInt32(&stop) != 0 {
+				break
+			}
+			in <- j
+		}
+		close(in)
+		wg.Wait()
+	}
+	return ctx.Err()
+}
+
+// AllMoves returns every move that can be made on b with the letters of
+// rack, each once, in canonical order.
+func AllMoves(dict *Node, b *board.Board, rack []Letter) []Move {
+	return collectMoves(dict, b, rack, GenOptions{})
+}
+
+func collectMoves(dict *Node, b *board.Board, rack []Letter,
+	opts GenOptions) []Move {
+	var moves []Move
+	_ = GenerateMoves(context.Background(), dict, b, rack, opts,
+		func(m Move) bool {
+			moves = append(moves, m)
+			return true
+		})
+	sort.Slice(moves, func(i, j int) bool {
+		return lessMove(moves[i], moves[j])
+	})
+	return moves
+}
+
+// lessMove is the canonical order of moves: by row, column, direction, word
+// and then the tiles placed, with real letters before blanks.
+func lessMove(a, b Move) bool {
+	if a.Row != b.Row {
+		return a.Row < b.Row
+	}
+	if a.Col != b.Col {
+		return a.Col < b.Col
+	}
+	if a.Dir != b.Dir {
+		return a.Dir < b.Dir
+	}
+	if a.Word != b.Word {
+		return a.Word < b.Word
+	}
+	for i := 0; i < len(a.Tiles) && i < len(b.Tiles); i++ {
+		pa, pb := a.Tiles[i], b.Tiles[i]
+		switch {
+		case pa.Row != pb.Row:
+			return pa.Row < pb.Row
+		case pa.Col != pb.Col:
+			return pa.Col < pb.Col
+		case pa.Letter != pb.Letter:
+			return pa.Letter < pb.Letter
+		case pa.Blank != pb.Blank:
+			return !pa.Blank
+		}
+	}
+	return len(a.Tiles) < len(b.Tiles)
+}
+
+// HasMove reports whether any move can be made on b with the letters of
+// rack. It stops at the first move it finds.
+func HasMove(dict *Node, b *board.Board, rack []Letter) bool {
+	var found bool
+	_ = GenerateMoves(context.Background(), dict, b, rack,
+		GenOptions{Parallelism: 1}, func(Move) bool {
+			found = true
+			return false
+		})
+	return found
+}
+
+// TopMoves returns the n moves with the highest score, best first, without
+// keeping every move in memory.
+func TopMoves(ctx context.Context, dict *Node, b *board.Board, rack []Letter,
+	n int, opts GenOptions, score func(Move) int) ([]Move, error) {
+	h := &moveHeap{}
+	err := GenerateMoves(ctx, dict, b, rack, opts, func(m Move) bool {
+		sm := scoredMove{m, score(m)}
+		if h.Len() < n {
+			heap.Push(h, sm)
+		} else if n > 0 && h.less(h.moves[0], sm) {
+			h.moves[0] = sm
+			heap.Fix(h, 0)
+		}
+		return true
+	})
+	moves := make([]Move, h.Len())
+	for i := len(moves) - 1; i >= 0; i-- {
+		moves[i] = heap.Pop(h).(scoredMove).m
+	}
+	return moves, err
+}
+
+type scoredMove struct {
+	m     Move
+	score int
+}
+
+// moveHeap is a min-heap of moves by score.
+type moveHeap struct {
+	moves []scoredMove
+}
+
+func (h *moveHeap) Len() int {
+	return len(h.moves)
+}
+
+func (h *moveHeap) Less(i, j int) bool {
+	return h.less(h.moves[i], h.moves[j])
+}
+
+// less orders moves by score and then by reverse canonical order, so that
+// ties are settled the same way however generation was scheduled.
+func (h *moveHeap) less(a, b scoredMove) bool {
+	if a.score != b.score {
+		return a.score < b.score
+	}
+	return lessMove(b.m, a.m)
+}
+
+func (h *moveHeap) Swap(i, j int) {
+	h.moves[i], h.moves[j] = h.moves[j], h.moves[i]
+}
+
+func (h *moveHeap) Push(x interface{}) {
+	h.moves = append(h.moves, x.(scoredMove))
+}
+
+func (h *moveHeap) Pop() interface{} {
+	m := h.moves[len(h.moves)-1]
+	h.moves = h.moves[:len(h.moves)-1]
+	return m
+}
+
+// anchorJob is an anchor to generate across moves from, on a board that may
+// be the transpose of the real one.
+type anchorJob struct {
+	anchor     *board.Tile
+	transposed bool
+}
+
+func anchorJobs(b *board.Board, transposed bool) []anchorJob {
+	anchors := b.Anchors()
+	if len(anchors) == 0 {
+		anchors = []*board.Tile{b.Center()}
+	}
+	jobs := make([]anchorJob, len(anchors))
+	for i, a := range anchors {
+		jobs[i] = anchorJob{a, transposed}
+	}
+	return jobs
+}
+
+// scratch holds the buffers a generator works in, so that they can be
+// reused from one anchor to the next.
+type scratch struct {
+	// counts holds how many of each letter of the alphabet are on the rack,
+	// by letter index, and letters holds which ones there is at least one of.
+	counts  [64]int
+	letters LetterSet
+	// word holds the letters of the word being built, and blanks which of
+	// them were played with a blank.
+	word   []Letter
+	blanks []bool
+}
+
+var blankIndex = Blank.Index()
+
+func (s *scratch) reset(rack []Letter) {
+	s.counts = [64]int{}
+	s.letters = 0
+	for _, l := range rack {
+		s.counts[l.Index()]++
+		s.letters = s.letters.With(l)
+	}
+	s.word = s.word[:0]
+	s.blanks = s.blanks[:0]
+}
+
+// available returns the letters that can be played from the rack.
+func (s *scratch) available() LetterSet {
+	if s.counts[blankIndex] > 0 {
+		return AllLetters
+	}
+	return s.letters
+}
+
+func (s *scratch) push(l Letter, blank bool) {
+	taken := l
+	if blank {
+		taken = Blank
+	}
+	i := taken.Index()
+	s.counts[i]--
+	if s.counts[i] == 0 {
+		s.letters = s.letters.Without(taken)
+	}
+	s.word = append(s.word, l)
+	s.blanks = append(s.blanks, blank)
+}
+
+func (s *scratch) pop() {
+	n := len(s.word) - 1
+	taken := s.word[n]
+	if s.blanks[n] {
+		taken = Blank
+	}
+	s.counts[taken.Index()]++
+	s.letters = s.letters.With(taken)
+	s.word = s.word[:n]
+	s.blanks = s.blanks[:n]
+}
+
+// generator finds the across moves that go through one anchor.
+type generator struct {
+	dict       *Node
+	anchor     *board.Tile
+	transposed bool
+	stop       *int32
+	emit       func(Move)
+	s          *scratch
+}
+
+func (g *generator) stopped() bool {
+	return atomic.LoadInt32(g.stop) != 0
+}
+
+func (g *generator) run(s *scratch, rack []Letter) {
+	g.s = s
+	s.reset(rack)
+	if g.anchor.Left().Empty() {
+		g.leftPart(g.dict, getK(g.anchor))
+	} else {
+		left := g.anchor.GatherLeft()
+		for _, r := range left {
+			s.word = append(s.word, Letter(r))
+			s.blanks = append(s.blanks, false)
+		}
+		g.extendRight(g.dict.Search(left), g.anchor)
+	}
+}
+
+func getK(anchor *board.Tile) int {
+	var k int
+	t := anchor.Left()
+	for t != nil && t.Empty() && t.EmptyAround() {
+		k++
+		t = t.Left()
+	}
+	return k
+}
+
+// each calls fn for every way of playing a letter in ls from the rack: with
+// the letter itself and with a blank.
+func (g *generator) each(ls LetterSet, fn func()) {
+	hasBlank := g.s.counts[blankIndex] > 0
+	for ; ls != 0; ls &= ls - 1 {
+		l := ls.First()
+		if g.s.counts[l.Index()] > 0 {
+			g.s.push(l, false)
+			fn()
+			g.s.pop()
+		}
+		if hasBlank {
+			g.s.push(l, true)
+			fn()
+			g.s.pop()
+		}
+	}
+}
+
+func (g *generator) leftPart(node *Node, limit int) {
+	g.extendRight(node, g.anchor)
+	if limit > 0 {
+		g.each(node.EdgeSet()&g.s.available(), func() {
+			l := g.s.word[len(g.s.word)-1]
+			g.leftPart(node.Child(l), limit-1)
+		})
+	}
+}
+
+func (g *generator) extendRight(node *Node, square *board.Tile) {
+	if g.stopped() {
+		return
+	}
+	if square.Empty() {
+		if node.Accept() && g.anchor.Col() < square.Col() {
+			g.found(square.Left())
+		}
+		g.each(node.EdgeSet()&g.s.available()&square.YCrossCheck(), func() {
+			l := g.s.word[len(g.s.word)-1]
+			g.next(node.Child(l), square)
+		})
+	} else if n := node.Child(square.Letter()); n != nil {
+		g.s.word = append(g.s.word, square.Letter())
+		g.s.blanks = append(g.s.blanks, square.Blank())
+		g.next(n, square)
+		g.s.word = g.s.word[:len(g.s.word)-1]
+		g.s.blanks = g.s.blanks[:len(g.s.blanks)-1]
+	}
+}
+
+// next carries on past square, the last letter of the word so far. At the
+// edge of the board, the word can only end there.
+func (g *generator) next(node *Node, square *board.Tile) {
+	if right := square.Right(); right != nil {
+		g.extendRight(node, right)
+	} else if node.Accept() {
+		g.found(square)
+	}
+}
+
+// found emits the word built so far, which ends on end.
+func (g *generator) found(end *board.Tile) {
+	start := end.LeftN(len(g.s.word) - 1)
+	m := Move{
+		Row:  start.Row(),
+		Col:  start.Col(),
+		Dir:  DirAcross,
+		Word: Word(string(g.s.word)),
+	}
+	var last *board.Tile
+	for i, l := range g.s.word {
+		t := start.RightN(i)
+		if t.Empty() {
+			m.Tiles = append(m.Tiles, Placement{
+				Row:    t.Row(),
+				Col:    t.Col(),
+				Letter: l,
+				Blank:  g.s.blanks[i],
+			})
+			last = t
+		}
+	}
+	// A move that puts down a single tile forming words both across and down
+	// would otherwise be found by both passes, so only the across pass keeps
+	// it.
+	if g.transposed && len(m.Tiles) == 1 &&
+		(!last.Up().Empty() || !last.Down().Empty()) {
+		return
+	}
+	if g.transposed {
+		m = m.Transposed()
+	}
+	g.emit(m)
+}
@@ -0,0 +1,213 @@
+package scrabble
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/tmazeika/scrabble-go/internal/board"
+	"github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/move"
+	"github.com/tmazeika/scrabble-go/internal/rules"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func movegenFixture() (*dict.Node, *board.Board, []dict.Letter) {
+	d := dict.NewNode()
+	for _, w := range []dict.Word{"AT", "TA", "EAT", "TEA", "ATE", "ETA",
+		"EATS", "SEAT", "TEAS", "EAST", "SATE", "CAT", "CATS", "SCAT", "ACT",
+		"ACTS", "CAST", "CASE", "ACE", "ACES", "SAT", "SET", "TEE", "AA",
+		"AE"} {
+		d.Insert(w)
+	}
+	b := board.New(rules.BoardSize)
+	b.SetAcross(7, 6, "CAT")
+	b.SetDown(7, 8, "TEAS")
+	return d, b, []dict.Letter("TESACXE")
+}
+
+func TestGenerateMoves(t *testing.T) {
+	d, b, rack := movegenFixture()
+	all := AllMoves(d, b, rack)
+	assert.NotEmpty(t, all)
+	pool := NewPool(3)
+	defer pool.Close()
+	for _, opts := range []GenOptions{{Parallelism: 1}, {Parallelism: 2},
+		{Parallelism: 8}, {Pool: pool}} {
+		var moves []move.Move
+		err := GenerateMoves(context.Background(), d, b, rack, opts,
+			func(m move.Move) bool {
+				moves = append(moves, m)
+				return true
+			})
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, all, moves)
+	}
+}
+
+// placedKey identifies a move by the tiles it puts on the board.
+func placedKey(m move.Move) string {
+	key := ""
+	for _, p := range m.Tiles {
+		key += fmt.Sprintf("%d,%d,%c,%t;", p.Row, p.Col, p.Letter, p.Blank)
+	}
+	return key
+}
+
+func TestAllMoves_Canonical(t *testing.T) {
+	d, b, rack := movegenFixture()
+	moves := AllMoves(d, b, rack)
+	seen := make(map[string]move.Move)
+	var singles int
+	for _, m := range moves {
+		key := placedKey(m)
+		if prev, ok := seen[key]; ok {
+			t.Errorf("%v and %v place the same tiles", prev, m)
+		}
+		seen[key] = m
+		if strings.Count(key, ";") == 1 {
+			singles++
+		}
+	}
+	assert.NotZero(t, singles)
+	assert.True(t, sort.SliceIsSorted(moves, func(i, j int) bool {
+		return lessMove(moves[i], moves[j])
+	}))
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, moves, AllMoves(d, b, rack))
+	}
+}
+
+func TestAllMoves_Blanks(t *testing.T) {
+	d, b, _ := movegenFixture()
+	moves := AllMoves(d, b, []dict.Letter("T_"))
+	assert.Contains(t, moves, move.Move{Row: 7, Col: 6, Word: "CATS",
+		Tiles: []move.Placement{{Row: 7, Col: 9, Letter: 'S', Blank: true}}})
+	assert.Contains(t, moves, move.Move{Row: 7, Col: 5, Word: "SCAT",
+		Tiles: []move.Placement{{Row: 7, Col: 5, Letter: 'S', Blank: true}}})
+	for _, m := range moves {
+		m2, err := b.Complete(move.Move{Tiles: m.Tiles})
+		assert.Nil(t, err)
+		assert.Equal(t, m, m2)
+		assert.Len(t, m.Leave([]dict.Letter("T_")), 2-len(m.Tiles))
+	}
+}
+
+func TestGenerateMoves_Stop(t *testing.T) {
+	d, b, rack := movegenFixture()
+	for _, p := range []int{1, 4} {
+		var n int
+		err := GenerateMoves(context.Background(), d, b, rack,
+			GenOptions{Parallelism: p}, func(move.Move) bool {
+				n++
+				return n < 3
+			})
+		assert.Nil(t, err)
+		assert.Equal(t, 3, n)
+	}
+	assert.True(t, HasMove(d, b, rack))
+	assert.False(t, HasMove(d, b, []dict.Letter("XXX")))
+}
+
+func TestGenerateMoves_Cancel(t *testing.T) {
+	d, b, rack := movegenFixture()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var n int
+	err := GenerateMoves(ctx, d, b, rack, GenOptions{Parallelism: 4},
+		func(move.Move) bool {
+			n++
+			return true
+		})
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 0, n)
+}
+
+func TestTopMoves(t *testing.T) {
+	d, b, rack := movegenFixture()
+	all := AllMoves(d, b, rack)
+	top, err := TopMoves(context.Background(), d, b, rack, 5, GenOptions{},
+		b.Points)
+	assert.Nil(t, err)
+	assert.Len(t, top, 5)
+	for i := 1; i < len(top); i++ {
+		assert.GreaterOrEqual(t, b.Points(top[i-1]), b.Points(top[i]))
+	}
+	best := MostPointsStrategy(&Game{Board: b}, all)
+	assert.Equal(t, b.Points(best), b.Points(top[0]))
+}
+
+var (
+	benchDictOnce sync.Once
+	benchDict     *dict.Node
+	benchDictErr  error
+)
+
+func loadBenchDict() (*dict.Node, error) {
+	benchDictOnce.Do(func() {
+		benchDict, benchDictErr = dict.Load(filepath.Join("..", "..",
+			dict.Dict))
+	})
+	return benchDict, benchDictErr
+}
+
+// BenchmarkGenerateMoves_SelfPlay measures move generation throughput while
+// many self-play games run at once, as they do in MCTS rollouts. Each op is
+// one turn of one game.
+func BenchmarkGenerateMoves_SelfPlay(b *testing.B) {
+	d, err := loadBenchDict()
+	if err != nil {
+		b.Fatal(err)
+	}
+	pool := NewPool(runtime.GOMAXPROCS(0))
+	defer pool.Close()
+	for _, bm := range []struct {
+		name string
+		gen  GenOptions
+	}{
+		{"GoroutinesPerCall", GenOptions{}},
+		{"SharedPool", GenOptions{Pool: pool}},
+		{"SingleThreaded", GenOptions{Parallelism: 1}},
+	} {
+		bm := bm
+		b.Run(bm.name, func(b *testing.B) {
+			b.SetParallelism(4)
+			b.RunParallel(func(pb *testing.PB) {
+				var g *Game
+				for pb.Next() {
+					if g == nil || g.Over() {
+						p1 := NewComputerPlayer("P1", MostPointsStrategy)
+						p2 := NewComputerPlayer("P2", MostPointsStrategy)
+						// Solving endgames would swamp move generation.
+						p1.SetEndgame(nil)
+						p2.SetEndgame(nil)
+						g = NewGame(d, p1, p2)
+						g.Gen = bm.gen
+					}
+					if _, err := g.PlayRound(); err != nil {
+						b.Error(err)
+						return
+					}
+				}
+			})
+		})
+	}
+}
+
+func TestAllMoves_Order(t *testing.T) {
+	d, b, _ := movegenFixture()
+	rack := []dict.Letter("TESAC_E")
+	want := AllMoves(d, b, rack)
+	for i := 1; i < len(want); i++ {
+		assert.True(t, lessMove(want[i-1], want[i]), "%v, %v", want[i-1],
+			want[i])
+	}
+	for i := 0; i < 10; i++ {
+		moves := collectMoves(d, b, rack, GenOptions{Parallelism: 8})
+		assert.Equal(t, want, moves)
+	}
+}
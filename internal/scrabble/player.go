@@ -6,6 +6,7 @@ import (
 	. "github.com/tmazeika/scrabble-go/internal/dict"
 	. "github.com/tmazeika/scrabble-go/internal/move"
 	"github.com/tmazeika/scrabble-go/internal/rules"
+	"github.com/tmazeika/scrabble-go/internal/zobrist"
 	"strings"
 )
 
@@ -15,6 +16,8 @@ type Player interface {
 	Points() int
 	AddPoints(points int)
 	Rack() []Letter
+	SetRack(letters []Letter)
+	RackHash() uint64
 	InRack(letters []Letter) bool
 	UseRack(letters []Letter)
 	DrawFrom(bag *bag.Bag)
@@ -23,9 +26,10 @@ type Player interface {
 }
 
 type basePlayer struct {
-	name   string
-	points int
-	rack   []Letter
+	name     string
+	points   int
+	rack     []Letter
+	rackHash uint64
 }
 
 func (p *basePlayer) Name() string {
@@ -46,33 +50,61 @@ func (p *basePlayer) Rack() []Letter {
 	return rack
 }
 
-func (p *basePlayer) InRack(letters []Letter) bool {
-	rack := p.Rack()
+func (p *basePlayer) SetRack(letters []Letter) {
+	p.rack = make([]Letter, 0, len(letters))
+	p.rackHash = 0
+	p.addToRack(letters...)
+}
+
+// RackHash returns the Zobrist hash of the letters on the rack, regardless
+// of their order.
+func (p *basePlayer) RackHash() uint64 {
+	return p.rackHash
+}
+
+func (p *basePlayer) addToRack(letters ...Letter) {
 	for _, l := range letters {
-		if !Contains(rack, l) {
-			return false
+		p.rackHash ^= zobrist.Rack(l, count(p.rack, l))
+		p.rack = append(p.rack, l)
+	}
+}
+
+func count(letters []Letter, letter Letter) int {
+	var n int
+	for _, l := range letters {
+		if l == letter {
+			n++
 		}
-		rack = Remove(rack, l)
 	}
-	return true
+	return n
+}
+
+func (p *basePlayer) InRack(letters []Letter) bool {
+	return inRack(p.rack, letters)
 }
 
 func (p *basePlayer) UseRack(letters []Letter) {
 	for _, l := range letters {
-		p.rack = Remove(p.rack, l)
+		if Contains(p.rack, l) {
+			p.rack = Remove(p.rack, l)
+			p.rackHash ^= zobrist.Rack(l, count(p.rack, l))
+		}
 	}
 }
 
 func (p *basePlayer) DrawFrom(bag *bag.Bag) {
-	p.rack = append(p.rack, bag.Draw(rules.RackSize-len(p.rack))...)
+	p.addToRack(bag.Draw(rules.RackSize - len(p.rack))...)
 }
 
+// CopyAsAI returns a ComputerPlayer like p that plays by strategy, endgames
+// included.
 func (p *basePlayer) CopyAsAI(strategy StrategyFunc) Player {
 	p2 := ComputerPlayer{
 		basePlayer: basePlayer{
-			name:   p.name,
-			points: p.points,
-			rack:   make([]Letter, len(p.rack)),
+			name:     p.name,
+			points:   p.points,
+			rack:     make([]Letter, len(p.rack)),
+			rackHash: p.rackHash,
 		},
 		strategy: strategy,
 	}
@@ -0,0 +1,240 @@
+package scrabble
+
+import (
+	"github.com/tmazeika/scrabble-go/internal/bag"
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/leaves"
+	. "github.com/tmazeika/scrabble-go/internal/move"
+	"github.com/tmazeika/scrabble-go/internal/ttable"
+	"math"
+	"sort"
+)
+
+// PreEndgameOptions configures SolvePreEndgame.
+type PreEndgameOptions struct {
+	// Candidates is how many of the moves with the most Equity are
+	// analyzed, or zero for all of them. Passing, and the highest scoring
+	// move that keeps each number of tiles in the bag, are analyzed too.
+	Candidates int
+	// Endgame is how the endgame after each draw is solved. The zero value
+	// means DefaultEndgameOptions, with a transposition table shared by
+	// every endgame of the analysis.
+	Endgame EndgameOptions
+	// Leaves values leaves when choosing candidates. Nil means
+	// leaves.Heuristic.
+	Leaves *leaves.Table
+}
+
+// PreEndgameResult is what SolvePreEndgame found for a candidate.
+type PreEndgameResult struct {
+	Move Move
+	// Wins is the chance of winning, with draws counting half.
+	Wins float64
+	// Spread is the expected final spread.
+	Spread float64
+	// Draws is how many different orders of the bag were solved.
+	Draws int
+}
+
+// SolvePreEndgame analyzes the moves of game's current player when there are
+// a few tiles in the bag and one opponent. For each candidate it goes
+// through every order that the unseen tiles could be in the bag, with the
+// rest on the opponent's rack, weighted by its chance. After the candidate,
+// every move of both players is searched while the bag still has tiles, each
+// knowing the other's rack and the order of the bag, and the endgame is then
+// solved with SolveEndgame. It returns the candidates with the best chance of
+// winning first, then by spread.
+func SolvePreEndgame(game *Game, moves []Move,
+	opts PreEndgameOptions) []PreEndgameResult {
+	if len(game.Players) != 2 {
+		panic("pre-endgame needs two players")
+	}
+	table := opts.Leaves
+	if table == nil {
+		table = leaves.Heuristic()
+	}
+	seat := game.Round % len(game.Players)
+	s := preEndgame{endgame: opts.Endgame, seat: seat}
+	if s.endgame == (EndgameOptions{}) {
+		s.endgame = DefaultEndgameOptions
+		s.endgame.Table = ttable.New(1 << 16)
+	}
+	var results []PreEndgameResult
+	for _, m := range preEndgameCandidates(game, moves, opts.Candidates,
+		table) {
+		r := PreEndgameResult{Move: m}
+		s.eachDraw(game.Unseen(seat), game.Bag.Len(), nil, 1,
+			func(draw []Letter, p float64) {
+				state := s.deal(game, draw)
+				if _, err := state.PlayMove(m); err != nil {
+					panic(err)
+				}
+				passes := 0
+				if m.Skip {
+					passes = 1
+				}
+				spread := s.finish(state, passes)
+				r.Spread += p * float64(spread)
+				switch {
+				case spread > 0:
+					r.Wins += p
+				case spread == 0:
+					r.Wins += p / 2
+				}
+				r.Draws++
+			})
+		results = append(results, r)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Wins != results[j].Wins {
+			return results[i].Wins > results[j].Wins
+		}
+		return results[i].Spread > results[j].Spread
+	})
+	return results
+}
+
+// preEndgameCandidates returns the moves to analyze: those with the most
+// Equity, passing, and the best fishing plays, which leave tiles in the bag
+// for the opponent to draw.
+func preEndgameCandidates(game *Game, moves []Move, n int,
+	table *leaves.Table) []Move {
+	rack := game.CurrentPlayer().Rack()
+	byEquity := make([]Move, len(moves))
+	copy(byEquity, moves)
+	equity := make(map[string]float64, len(moves))
+	for _, m := range moves {
+		equity[m.String()] = Equity(game, rack, m, table)
+	}
+	sort.SliceStable(byEquity, func(i, j int) bool {
+		return equity[byEquity[i].String()] > equity[byEquity[j].String()]
+	})
+	if n > 0 && len(byEquity) > n {
+		byEquity = byEquity[:n]
+	}
+	candidates := append(byEquity, Move{Skip: true})
+	seen := make(map[string]bool)
+	for _, m := range candidates {
+		seen[m.String()] = true
+	}
+	// The highest scoring move using each number of tiles that is fewer
+	// than the bag holds.
+	fish := make(map[int]Move)
+	for _, m := range moves {
+		used := len(m.TilesUsed())
+		if used >= game.Bag.Len() {
+			continue
+		}
+		if best, ok := fish[used]; !ok ||
+			game.Board.Points(m) > game.Board.Points(best) {
+			fish[used] = m
+		}
+	}
+	for used := 1; used < game.Bag.Len(); used++ {
+		if m, ok := fish[used]; ok && !seen[m.String()] {
+			candidates = append(candidates, m)
+			seen[m.String()] = true
+		}
+	}
+	return candidates
+}
+
+type preEndgame struct {
+	endgame EndgameOptions
+	seat    int
+}
+
+// eachDraw calls fn with every order of n tiles that can be drawn from
+// unseen, after those in draw, and its chance p.
+func (s *preEndgame) eachDraw(unseen []Letter, n int, draw []Letter,
+	p float64, fn func(draw []Letter, p float64)) {
+	if len(draw) == n {
+		fn(draw, p)
+		return
+	}
+	counts := make(map[Letter]int)
+	for _, l := range unseen {
+		counts[l]++
+	}
+	letters := make([]Letter, 0, len(counts))
+	for l := range counts {
+		letters = append(letters, l)
+	}
+	sort.Slice(letters, func(i, j int) bool {
+		return letters[i] < letters[j]
+	})
+	for _, l := range letters {
+		s.eachDraw(Remove(unseen, l), n, append(draw, l),
+			p*float64(counts[l])/float64(len(unseen)), fn)
+	}
+}
+
+// deal returns a copy of game with draw in the bag, in order, and the rest
+// of the unseen tiles on the opponent's rack.
+func (s *preEndgame) deal(game *Game, draw []Letter) *Game {
+	state := game.AICopy(MostPointsStrategy)
+	rack := game.Unseen(s.seat)
+	for _, l := range draw {
+		rack = Remove(rack, l)
+	}
+	state.Players[1-s.seat].SetRack(rack)
+	state.Bag = bag.FromLetters(draw)
+	return state
+}
+
+// finish plays state out and returns the final spread for the analyzing
+// player. passes is how many turns in a row have been passed.
+func (s *preEndgame) finish(state *Game, passes int) int {
+	v := s.search(state, passes, math.MinInt32, math.MaxInt32)
+	if state.Round%2 != s.seat {
+		return -v
+	}
+	return v
+}
+
+// search returns the final spread for the current player of state with the
+// best play by both players, by alpha-beta search while the bag has tiles and
+// by SolveEndgame once it is empty. passes is how many turns in a row have
+// been passed.
+func (s *preEndgame) search(state *Game, passes, alpha, beta int) int {
+	if state.Over() {
+		return spread(state)
+	}
+	if passes >= 2 {
+		return spread(state) - rackPoints(state.CurrentPlayer().Rack()) +
+			rackPoints(otherPlayer(state).Rack())
+	}
+	if state.Bag.Empty() {
+		return SolveEndgame(state, s.endgame).Spread
+	}
+	rack := state.CurrentPlayer().Rack()
+	moves := state.AllMoves(rack)
+	points := make([]int, len(moves))
+	for i, m := range moves {
+		points[i] = state.Board.Points(m)
+	}
+	sort.Stable(byPoints{moves, points})
+	best := math.MinInt32
+	for _, m := range append(moves, Move{Skip: true}) {
+		u, err := state.PlayMove(m)
+		if err != nil {
+			panic(err)
+		}
+		next := 0
+		if m.Skip {
+			next = passes + 1
+		}
+		v := -s.search(state, next, -beta, -alpha)
+		state.UndoMove(u)
+		if v > best {
+			best = v
+		}
+		if best > alpha {
+			alpha = best
+		}
+		if alpha >= beta {
+			break
+		}
+	}
+	return best
+}
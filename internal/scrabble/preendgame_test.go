@@ -0,0 +1,81 @@
+package scrabble
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/tmazeika/scrabble-go/internal/bag"
+	"github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/move"
+	"math/rand"
+	"testing"
+)
+
+func TestSolvePreEndgame(t *testing.T) {
+	rand.Seed(0)
+	g := endgameFixture("SEQI", "ZAT")
+	g.Bag = bag.FromLetters([]dict.Letter("AS"))
+	rack := g.CurrentPlayer().Rack()
+	results := SolvePreEndgame(g, g.AllMoves(rack), PreEndgameOptions{
+		Candidates: 1,
+	})
+	// The top move, passing and the best play of one tile, which leaves a
+	// tile in the bag.
+	assert.Len(t, results, 3)
+	var pass, fish bool
+	for i, r := range results {
+		// The unseen tiles are AASTZ, so the bag holds any two of the four
+		// letters in order but SS, TT and ZZ.
+		assert.Equal(t, 4*4-3, r.Draws)
+		assert.True(t, 0 <= r.Wins && r.Wins <= 1)
+		if i > 0 {
+			assert.True(t, r.Wins <= results[i-1].Wins)
+		}
+		pass = pass || r.Move.Skip
+		fish = fish || len(r.Move.TilesUsed()) == 1
+	}
+	assert.True(t, pass)
+	assert.True(t, fish)
+	assert.Equal(t, rack, g.CurrentPlayer().Rack())
+	assert.Equal(t, 2, g.Bag.Len())
+}
+
+func TestSolvePreEndgame_OneDraw(t *testing.T) {
+	// With every unseen tile the same, there is one draw, and each result is
+	// the endgame after it.
+	g := endgameFixture("SEQI", "ZZ")
+	g.Bag = bag.FromLetters([]dict.Letter("Z"))
+	m, err := move.Parse("8G (CAT)S")
+	assert.Nil(t, err)
+	results := SolvePreEndgame(g, []move.Move{m}, PreEndgameOptions{})
+	assert.Len(t, results, 2)
+	for _, r := range results {
+		assert.Equal(t, 1, r.Draws)
+		state := g.AICopy(MostPointsStrategy)
+		_, err := state.PlayMove(r.Move)
+		assert.Nil(t, err)
+		if r.Move.Skip {
+			// The opponent, knowing the draw, picks the reply with the best
+			// endgame after it, or passes to end the game.
+			rack := state.CurrentPlayer().Rack()
+			best := spread(state) - rackPoints(rack) +
+				rackPoints(otherPlayer(state).Rack())
+			for _, m := range state.AllMoves(rack) {
+				reply := state.AICopy(MostPointsStrategy)
+				_, err := reply.PlayMove(m)
+				assert.Nil(t, err)
+				assert.True(t, reply.Bag.Empty())
+				v := -spread(reply)
+				if !reply.Over() {
+					v = -SolveEndgame(reply, DefaultEndgameOptions).Spread
+				}
+				if v > best {
+					best = v
+				}
+			}
+			assert.Equal(t, float64(-best), r.Spread)
+			continue
+		}
+		assert.True(t, state.Bag.Empty())
+		assert.Equal(t, float64(-SolveEndgame(state, EndgameOptions{}).Spread),
+			r.Spread)
+	}
+}
@@ -0,0 +1,187 @@
+package scrabble
+
+import (
+	"github.com/tmazeika/scrabble-go/internal/leaves"
+	. "github.com/tmazeika/scrabble-go/internal/move"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// SimOptions configures Sim.
+type SimOptions struct {
+	// Candidates is how many of the moves with the most Equity are simmed,
+	// or zero for all of them.
+	Candidates int
+	// Plies is how many turns each sim plays, counting the candidate.
+	Plies int
+	// Iterations is the most times each candidate is simmed and Budget the
+	// most time spent simming. Zero means no limit, but with neither set
+	// each candidate is simmed once.
+	Iterations int
+	Budget     time.Duration
+	// Leaves values the leaves of the moves played in sims and of the rack
+	// the player is left with. Nil means leaves.Heuristic.
+	Leaves *leaves.Table
+	// Prune, if set, stops simming a candidate once its mean plus Prune
+	// standard errors is below the best candidate's mean less Prune of its
+	// standard errors, so that the two intervals do not overlap, after
+	// MinPruneIterations.
+	Prune float64
+}
+
+// MinPruneIterations is how many times every candidate is simmed before any
+// are pruned.
+const MinPruneIterations = 8
+
+// SimStats is what simming a candidate found. Each sim is valued by how much
+// the player's spread changed, plus the value of their leave at the end if
+// the bag is not empty.
+type SimStats struct {
+	Move Move
+	// Equity is the Equity of Move without simming.
+	Equity     float64
+	Iterations int
+	Mean       float64
+	Pruned     bool
+
+	// m2 is the sum of squared differences from Mean.
+	m2 float64
+}
+
+func (s *SimStats) add(v float64) {
+	s.Iterations++
+	d := v - s.Mean
+	s.Mean += d / float64(s.Iterations)
+	s.m2 += d * (v - s.Mean)
+}
+
+// StdErr returns the standard error of Mean.
+func (s *SimStats) StdErr() float64 {
+	if s.Iterations < 2 {
+		return math.Inf(1)
+	}
+	return math.Sqrt(s.m2 / float64(s.Iterations-1) / float64(s.Iterations))
+}
+
+// Sim sims the moves of game's current player with the most Equity against
+// the racks that the other players might have. Every iteration deals the
+// unseen tiles once with Game.Determinize and sims each remaining candidate
+// on that deal, with the players after the candidate playing by
+// NewEquityStrategy. It returns the candidates best first, followed by those
+// pruned.
+func Sim(game *Game, moves []Move, opts SimOptions) []*SimStats {
+	table := opts.Leaves
+	if table == nil {
+		table = leaves.Heuristic()
+	}
+	player := game.CurrentPlayer()
+	rack := player.Rack()
+	stats := make([]*SimStats, len(moves))
+	for i, m := range moves {
+		stats[i] = &SimStats{Move: m, Equity: Equity(game, rack, m, table)}
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		return stats[i].Equity > stats[j].Equity
+	})
+	if opts.Candidates > 0 && len(stats) > opts.Candidates {
+		stats = stats[:opts.Candidates]
+	}
+	if len(stats) < 2 {
+		return stats
+	}
+
+	seat := game.Round % len(game.Players)
+	strategy := NewEquityStrategy(table)
+	r := rand.New(rand.NewSource(rand.Int63()))
+	start := time.Now()
+	for it := 0; opts.Iterations <= 0 || it < opts.Iterations; it++ {
+		state := game.Determinize(seat, strategy, r)
+		for _, s := range stats {
+			if !s.Pruned {
+				s.add(sim(state, s.Move, player.Name(), opts.Plies, table))
+			}
+		}
+		if opts.Prune > 0 && it+1 >= MinPruneIterations && prune(stats,
+			opts.Prune) == 1 {
+			break
+		}
+		if opts.Budget > 0 && time.Since(start) >= opts.Budget ||
+			opts.Budget <= 0 && opts.Iterations <= 0 {
+			break
+		}
+	}
+	sort.SliceStable(stats, func(i, j int) bool {
+		if stats[i].Pruned != stats[j].Pruned {
+			return !stats[i].Pruned
+		}
+		return stats[i].Mean > stats[j].Mean
+	})
+	return stats
+}
+
+// sim plays m and then up to plies-1 more turns on state, values the result
+// for playerName and takes all of the moves back.
+func sim(state *Game, m Move, playerName string, plies int,
+	table *leaves.Table) float64 {
+	before := state.WonBy(playerName)
+	u, err := state.PlayMove(m)
+	if err != nil {
+		panic(err)
+	}
+	undos := []Undo{u}
+	defer func() {
+		for i := len(undos) - 1; i >= 0; i-- {
+			state.UndoMove(undos[i])
+		}
+	}()
+	for i := 1; i < plies && !state.Over(); i++ {
+		u, err := state.PlayMove(state.CurrentPlayer().Play(state))
+		if err != nil {
+			panic(err)
+		}
+		undos = append(undos, u)
+	}
+	v := float64(state.WonBy(playerName) - before)
+	if !state.Bag.Empty() {
+		for _, p := range state.Players {
+			if p.Name() == playerName {
+				v += table.Value(p.Rack())
+			}
+		}
+	}
+	return v
+}
+
+// prune marks the candidates that are clearly worse than the best one and
+// returns how many are left.
+func prune(stats []*SimStats, z float64) int {
+	var best *SimStats
+	for _, s := range stats {
+		if !s.Pruned && (best == nil || s.Mean > best.Mean) {
+			best = s
+		}
+	}
+	left := 0
+	for _, s := range stats {
+		if s != best && !s.Pruned &&
+			s.Mean+z*s.StdErr() < best.Mean-z*best.StdErr() {
+			s.Pruned = true
+		}
+		if !s.Pruned {
+			left++
+		}
+	}
+	return left
+}
+
+// NewSimStrategy returns a strategy that plays the best move found by Sim.
+func NewSimStrategy(opts SimOptions) StrategyFunc {
+	return func(game *Game, moves []Move) Move {
+		if len(moves) == 0 {
+			return Move{Skip: true}
+		}
+		return Sim(game, moves, opts)[0].Move
+	}
+}
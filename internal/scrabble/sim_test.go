@@ -0,0 +1,96 @@
+package scrabble
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/tmazeika/scrabble-go/internal/dict"
+	"math/rand"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSim(t *testing.T) {
+	rand.Seed(0)
+	d, err := dict.Load(filepath.Join("..", "..", dict.Dict))
+	if err != nil {
+		panic(err)
+	}
+	p1 := NewComputerPlayer("P1", MostPointsStrategy)
+	p2 := NewComputerPlayer("P2", MostPointsStrategy)
+	g := NewGame(d, p1, p2)
+	g.Gen = GenOptions{Parallelism: 1}
+	_, err = g.PlayRound()
+	assert.Nil(t, err)
+	rack1, rack2, bag := p1.Rack(), p2.Rack(), g.Bag.String()
+	moves := g.AllMoves(rack2)
+
+	stats := Sim(g, moves, SimOptions{Candidates: 5, Plies: 2,
+		Iterations: 20, Prune: 1})
+	assert.Len(t, stats, 5)
+	assert.False(t, stats[0].Pruned)
+	for i, s := range stats {
+		if s.Pruned {
+			assert.True(t, s.Iterations >= MinPruneIterations)
+			assert.True(t, s.Iterations < 20)
+		} else {
+			assert.Equal(t, stats[0].Iterations, s.Iterations)
+			assert.True(t, s.Mean <= stats[0].Mean)
+		}
+		if i > 0 && s.Pruned == stats[i-1].Pruned {
+			assert.True(t, s.Mean <= stats[i-1].Mean)
+		}
+	}
+
+	// Simming leaves the game as it was.
+	assert.Equal(t, rack1, p1.Rack())
+	assert.Equal(t, rack2, p2.Rack())
+	assert.Equal(t, bag, g.Bag.String())
+	assert.Equal(t, 1, g.Round)
+
+	// With only a budget, simming goes on until it runs out.
+	start := time.Now()
+	stats = Sim(g, moves, SimOptions{Candidates: 2, Plies: 2,
+		Budget: 50 * time.Millisecond})
+	assert.True(t, time.Since(start) >= 50*time.Millisecond)
+	assert.True(t, stats[0].Iterations > 0)
+
+	// The strategy plays the best candidate.
+	rand.Seed(1)
+	stats = Sim(g, moves, SimOptions{Candidates: 3, Plies: 2, Iterations: 4})
+	rand.Seed(1)
+	m := NewSimStrategy(SimOptions{Candidates: 3, Plies: 2,
+		Iterations: 4})(g, moves)
+	assert.Equal(t, stats[0].Move, m)
+}
+
+func TestPrune(t *testing.T) {
+	var stats []*SimStats
+	for _, vs := range [][]float64{{10, 12, 11}, {9, 13, 10}, {0, 1, 2}} {
+		s := &SimStats{}
+		for _, v := range vs {
+			s.add(v)
+		}
+		stats = append(stats, s)
+	}
+	assert.Equal(t, 11.0, stats[0].Mean)
+	assert.InDelta(t, 1/1.7320508, stats[0].StdErr(), 1e-6)
+	assert.Equal(t, 2, prune(stats, 2))
+	assert.True(t, stats[2].Pruned)
+	assert.Equal(t, 1, prune(stats, 0))
+	assert.False(t, stats[0].Pruned)
+}
+
+func TestPrune_Overlap(t *testing.T) {
+	best, other := &SimStats{}, &SimStats{}
+	for _, v := range []float64{10, 10.5, 11} {
+		best.add(v)
+		other.add(v - 1)
+	}
+	// other's mean is more than two of best's standard errors below best's,
+	// but their intervals overlap.
+	assert.True(t, other.Mean < best.Mean-2*best.StdErr())
+	assert.Equal(t, 2, prune([]*SimStats{best, other}, 2))
+	assert.False(t, other.Pruned)
+	assert.Equal(t, 1, prune([]*SimStats{best, other}, 1))
+	assert.True(t, other.Pruned)
+}
@@ -0,0 +1,97 @@
+package scrabble
+
+import (
+	"fmt"
+	"github.com/tmazeika/scrabble-go/internal/board"
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+	. "github.com/tmazeika/scrabble-go/internal/move"
+	"github.com/tmazeika/scrabble-go/internal/rules"
+	"strings"
+)
+
+// Result is what playing a move would do.
+type Result struct {
+	// Move is the move with its Word and Tiles filled in.
+	Move Move
+	// Words are the words the move makes, main word first.
+	Words []Word
+	Score int
+	// Leave is what stays on the rack before drawing.
+	Leave []Letter
+}
+
+// Validate checks that m can be played on b with the letters of rack and
+// returns what it would do. The error says why m is illegal. Neither b nor
+// rack is changed.
+func Validate(dict *Node, b *board.Board, rack []Letter, m Move) (Result,
+	error) {
+	if m.Skip {
+		if !inRack(rack, m.Exchange) {
+			return Result{}, fmt.Errorf("letters %q are not in rack: %v",
+				m.Exchange, m)
+		}
+		return Result{Move: m, Leave: m.Leave(rack)}, nil
+	}
+	m, err := b.Complete(m)
+	if err != nil {
+		return Result{}, err
+	}
+	needed := m.TilesUsed()
+	if len(needed) == 0 {
+		return Result{}, fmt.Errorf("must put down at least one letter from the rack: %v", m)
+	}
+	if !inRack(rack, needed) {
+		return Result{}, fmt.Errorf("required letters %q are not in rack: %v", needed, m)
+	}
+	bn, mn := b, m
+	// Normalize.
+	if m.Dir == DirDown {
+		bn = b.Transposed()
+		mn = m.Transposed()
+	}
+	if b.Center().Empty() && !passesThroughCenter(bn, mn) {
+		return Result{}, fmt.Errorf("first move must pass through the center: %v", m)
+	}
+	if !b.Center().Empty() && !touchesAnything(bn, mn) {
+		return Result{}, fmt.Errorf("move must build off an existing move: %v", m)
+	}
+	words := b.FormedWords(m)
+	if len(words) == 0 {
+		return Result{}, fmt.Errorf("move does not make a word: %v", m)
+	}
+	var invalid []string
+	for _, w := range words {
+		if !dict.Search(w).Accept() {
+			invalid = append(invalid, string(w))
+		}
+	}
+	if len(invalid) > 0 {
+		return Result{}, fmt.Errorf("invalid word(s) %s would be created: %v",
+			strings.Join(invalid, ", "), m)
+	}
+	return Result{
+		Move:  m,
+		Words: words,
+		Score: b.Points(m),
+		Leave: m.Leave(rack),
+	}, nil
+}
+
+// Validate checks that the current player can play m.
+func (g *Game) Validate(m Move) (Result, error) {
+	if m.Skip && len(m.Exchange) > 0 && g.Bag.Len() < rules.RackSize {
+		return Result{}, fmt.Errorf("cannot exchange with fewer than %d "+
+			"tiles in the bag: %v", rules.RackSize, m)
+	}
+	return Validate(g.Dict, g.Board, g.CurrentPlayer().Rack(), m)
+}
+
+func inRack(rack, letters []Letter) bool {
+	for _, l := range letters {
+		if !Contains(rack, l) {
+			return false
+		}
+		rack = Remove(rack, l)
+	}
+	return true
+}
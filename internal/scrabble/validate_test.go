@@ -0,0 +1,74 @@
+package scrabble
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/tmazeika/scrabble-go/internal/board"
+	"github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/move"
+	"github.com/tmazeika/scrabble-go/internal/rules"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	d, b, _ := movegenFixture()
+	rack := []dict.Letter("SEAT_XC")
+	hash := b.Hash()
+	str := b.String()
+
+	m, err := move.Parse("8G (CAT)s")
+	assert.Nil(t, err)
+	r, err := Validate(d, b, rack, m)
+	assert.Nil(t, err)
+	assert.Equal(t, Result{
+		Move: move.Move{Row: 7, Col: 6, Word: "CATS", Tiles: []move.Placement{
+			{Row: 7, Col: 9, Letter: 'S', Blank: true},
+		}},
+		Words: []dict.Word{"CATS"},
+		Score: 5,
+		Leave: []dict.Letter("SEATXC"),
+	}, r)
+
+	// ACE down through the C of CAT.
+	m, err = move.Parse("G7 A(C)E")
+	assert.Nil(t, err)
+	r, err = Validate(d, b, rack, m)
+	assert.Nil(t, err)
+	assert.Equal(t, []dict.Word{"ACE"}, r.Words)
+	assert.Equal(t, []dict.Letter("ST_XC"), r.Leave)
+
+	r, err = Validate(d, b, rack, move.Move{Skip: true,
+		Exchange: []dict.Letter("XX")})
+	assert.NotNil(t, err)
+	r, err = Validate(d, b, rack, move.Move{Skip: true,
+		Exchange: []dict.Letter("X_")})
+	assert.Nil(t, err)
+	assert.Equal(t, []dict.Letter("SEATC"), r.Leave)
+
+	for _, s := range []string{
+		"8G (CAT)",   // No tiles from the rack.
+		"8G (CAT)X",  // Not a word.
+		"8G (CAT)Q",  // Not in the rack.
+		"1A CAT",     // Not connected.
+		"8G (CAT)SS", // One S too many.
+		"8M TEAS",    // Off the board.
+	} {
+		m, err := move.Parse(s)
+		assert.Nil(t, err, s)
+		_, err = Validate(d, b, rack, m)
+		assert.NotNil(t, err, s)
+	}
+	assert.Equal(t, hash, b.Hash())
+	assert.Equal(t, str, b.String())
+	assert.Equal(t, []dict.Letter("SEAT_XC"), rack)
+
+	empty := board.New(rules.BoardSize)
+	for _, s := range []string{"1A CAT", "8H A", "8A CAT"} {
+		m, _ := move.Parse(s)
+		_, err = Validate(d, empty, rack, m)
+		assert.NotNil(t, err, s)
+	}
+	m, _ = move.Parse("H7 CAT")
+	r, err = Validate(d, empty, rack, m)
+	assert.Nil(t, err)
+	assert.Equal(t, 10, r.Score)
+}
@@ -0,0 +1,281 @@
+// Package train fits leave values by having equity players play each other.
+package train
+
+import (
+	"bufio"
+	"fmt"
+	"github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/leaves"
+	"github.com/tmazeika/scrabble-go/internal/scrabble"
+	"io"
+	"math"
+	"os"
+	"sync"
+)
+
+// Options configures Train.
+type Options struct {
+	// Games is how many games are played in each iteration.
+	Games int
+	// Iterations is the most iterations to run, counting those of a
+	// checkpoint.
+	Iterations int
+	// Tolerance stops training once the leave values of an iteration change
+	// by less than it on average.
+	Tolerance float64
+	// MaxLeave is the most tiles in a leave that gets a value.
+	MaxLeave int
+	// Smoothing is how many samples' worth of weight the previous value of a
+	// leave keeps against those seen in an iteration.
+	Smoothing float64
+	// Workers is how many games are played at once.
+	Workers int
+	// Checkpoint, if set, is a file that the table is written to after every
+	// iteration and that training resumes from.
+	Checkpoint string
+	// Progress, if set, is called after every iteration.
+	Progress func(Stats)
+}
+
+// Stats describes an iteration of training.
+type Stats struct {
+	Iteration int
+	// Samples is how many leaves were seen.
+	Samples int
+	// Seen is how many different leaves were seen.
+	Seen int
+	// Change is the average change in value of the leaves seen.
+	Change float64
+}
+
+// sample is what followed keeping a leave: the points scored on the
+// player's next turn less those of the opponent's reply.
+type sample struct {
+	leave string
+	diff  float64
+}
+
+// Train plays games between two players using NewEquityStrategy, each
+// iteration with the table fitted in the iteration before, starting from
+// leaves.Heuristic or the checkpoint. It returns the last table.
+func Train(d *dict.Node, opts Options) (*leaves.Table, error) {
+	table, start := leaves.Heuristic(), 0
+	if opts.Checkpoint != "" {
+		var err error
+		if table, start, err = readCheckpoint(opts.Checkpoint); err != nil {
+			return nil, err
+		}
+	}
+	for it := start; it < opts.Iterations; it++ {
+		samples, err := playGames(d, table, opts)
+		if err != nil {
+			return nil, err
+		}
+		next, stats := fit(table, samples, opts)
+		stats.Iteration = it + 1
+		table = next
+		if opts.Checkpoint != "" {
+			if err := writeCheckpoint(opts.Checkpoint, table,
+				stats.Iteration); err != nil {
+				return nil, err
+			}
+		}
+		if opts.Progress != nil {
+			opts.Progress(stats)
+		}
+		if stats.Change < opts.Tolerance {
+			break
+		}
+	}
+	return table, nil
+}
+
+// playGames plays opts.Games games, stopping at the first that fails.
+func playGames(d *dict.Node, table *leaves.Table, opts Options) ([]sample,
+	error) {
+	games := make(chan struct{})
+	var mu sync.Mutex
+	var samples []sample
+	var firstErr error
+	var wg sync.WaitGroup
+	workers := opts.Workers
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range games {
+				s, err := playGame(d, table)
+				mu.Lock()
+				samples = append(samples, s...)
+				if err != nil && firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := 0; i < opts.Games; i++ {
+		mu.Lock()
+		failed := firstErr != nil
+		mu.Unlock()
+		if failed {
+			break
+		}
+		games <- struct{}{}
+	}
+	close(games)
+	wg.Wait()
+	return samples, firstErr
+}
+
+// ply is a turn of a game: what the player kept, if there were tiles to
+// draw, and what they scored.
+type ply struct {
+	leave  []dict.Letter
+	draw   bool
+	points int
+}
+
+func playGame(d *dict.Node, table *leaves.Table) ([]sample, error) {
+	strategy := scrabble.NewEquityStrategy(table)
+	p1 := scrabble.NewComputerPlayer("P1", strategy)
+	p2 := scrabble.NewComputerPlayer("P2", strategy)
+	// Leaves are worth nothing in the endgame, so there is no need to solve
+	// it.
+	p1.SetEndgame(nil)
+	p2.SetEndgame(nil)
+	g := scrabble.NewGame(d, p1, p2)
+	g.Gen = scrabble.GenOptions{Parallelism: 1}
+	var plies []ply
+	for !g.Over() {
+		p := g.CurrentPlayer()
+		rack, before, draw := p.Rack(), p.Points(), !g.Bag.Empty()
+		m := p.Play(g)
+		if _, err := g.PlayMove(m); err != nil {
+			return nil, fmt.Errorf("%s played %v: %v", p.Name(), m, err)
+		}
+		plies = append(plies, ply{m.Leave(rack), draw, p.Points() - before})
+	}
+	var samples []sample
+	for i := 0; i+2 < len(plies); i++ {
+		if plies[i].draw && len(plies[i].leave) > 0 {
+			samples = append(samples, sample{
+				leave: leaves.Key(plies[i].leave),
+				diff:  float64(plies[i+2].points - plies[i+1].points),
+			})
+		}
+	}
+	return samples, nil
+}
+
+// fit works out new leave values from samples. The value of a leave is what
+// followed it compared to the average leave, pulled towards its old value by
+// opts.Smoothing. Leaves not seen keep their old value, or if they have none
+// are estimated using Singles fitted from the leaves of one tile.
+func fit(table *leaves.Table, samples []sample, opts Options) (*leaves.Table,
+	Stats) {
+	type total struct {
+		sum float64
+		n   float64
+	}
+	totals := make(map[string]*total)
+	var mean float64
+	for _, s := range samples {
+		mean += s.diff
+	}
+	if len(samples) > 0 {
+		mean /= float64(len(samples))
+	}
+	for _, s := range samples {
+		t := totals[s.leave]
+		if t == nil {
+			t = &total{}
+			totals[s.leave] = t
+		}
+		t.sum += s.diff - mean
+		t.n++
+	}
+	smooth := func(key string, prior float64) (float64, bool) {
+		t := totals[key]
+		if t == nil {
+			return prior, false
+		}
+		return (t.sum + opts.Smoothing*prior) / (t.n + opts.Smoothing), true
+	}
+
+	next := leaves.Heuristic()
+	for l := range table.Singles {
+		next.Singles[l], _ = smooth(leaves.Key([]dict.Letter{l}),
+			table.Value([]dict.Letter{l}))
+	}
+	stats := Stats{Samples: len(samples)}
+	leaves.Each(dict.LettersDist(), opts.MaxLeave, func(leave []dict.Letter) {
+		old, ok := table.Lookup(leave)
+		if v, seen := smooth(leaves.Key(leave), table.Value(leave)); seen {
+			stats.Seen++
+			stats.Change += math.Abs(v - table.Value(leave))
+			next.Set(leave, v)
+		} else if ok {
+			next.Set(leave, old)
+		} else {
+			next.Set(leave, next.Estimate(leave))
+		}
+	})
+	if stats.Seen > 0 {
+		stats.Change /= float64(stats.Seen)
+	}
+	return next, stats
+}
+
+func writeCheckpoint(filename string, table *leaves.Table,
+	iteration int) error {
+	tmp := filename + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	fmt.Fprintf(w, "# iteration %d\n", iteration)
+	if err := table.Write(w); err != nil {
+		f.Close()
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filename)
+}
+
+// readCheckpoint reads the table and iteration of a checkpoint, or returns
+// leaves.Heuristic if there is none yet.
+func readCheckpoint(filename string) (table *leaves.Table, iteration int,
+	err error) {
+	f, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return leaves.Heuristic(), 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer safeClose(f, &err)
+	if _, err := fmt.Fscanf(f, "# iteration %d\n", &iteration); err != nil {
+		return nil, 0, fmt.Errorf("%s: not a checkpoint: %v", filename, err)
+	}
+	if table, err = leaves.Read(f); err != nil {
+		return nil, 0, fmt.Errorf("%s: %v", filename, err)
+	}
+	return table, iteration, nil
+}
+
+func safeClose(closer io.Closer, err *error) {
+	if cerr := closer.Close(); cerr != nil && *err == nil {
+		*err = cerr
+	}
+}
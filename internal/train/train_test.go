@@ -0,0 +1,71 @@
+package train
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/tmazeika/scrabble-go/internal/dict"
+	"github.com/tmazeika/scrabble-go/internal/leaves"
+	"io/ioutil"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFit(t *testing.T) {
+	table := leaves.Heuristic()
+	samples := []sample{
+		{"S", 10}, {"S", 10}, {"Q", -10}, {"Q", -10},
+	}
+	next, stats := fit(table, samples, Options{MaxLeave: 2, Smoothing: 2})
+	assert.Equal(t, 4, stats.Samples)
+	assert.Equal(t, 2, stats.Seen)
+	// Half of what followed and half of the old value.
+	assert.Equal(t, (20+2*7.5)/4, next.Value([]dict.Letter("S")))
+	assert.Equal(t, (-20+2*(-7-5.0))/4, next.Value([]dict.Letter("Q")))
+	assert.Equal(t, next.Value([]dict.Letter("S")), next.Singles['S'])
+	// Leaves not seen are estimated from the new singles.
+	assert.Equal(t, next.Estimate([]dict.Letter("QS")),
+		next.Value([]dict.Letter("QS")))
+	assert.Equal(t, 1.0, next.Value([]dict.Letter("A")))
+}
+
+func TestTrain_Checkpoint(t *testing.T) {
+	rand.Seed(0)
+	d, err := dict.Load(filepath.Join("..", "..", dict.Dict))
+	if err != nil {
+		panic(err)
+	}
+	dir, err := ioutil.TempDir("", "train")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	var iterations []int
+	opts := Options{
+		Games:      2,
+		Iterations: 1,
+		MaxLeave:   2,
+		Smoothing:  10,
+		Workers:    2,
+		Checkpoint: filepath.Join(dir, "leaves.txt"),
+		Progress: func(s Stats) {
+			assert.True(t, s.Samples > 0)
+			iterations = append(iterations, s.Iteration)
+		},
+	}
+	first, err := Train(d, opts)
+	assert.Nil(t, err)
+	saved, iteration, err := readCheckpoint(opts.Checkpoint)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, iteration)
+	assert.Equal(t, first.Len(), saved.Len())
+
+	// Training again carries on from the checkpoint.
+	opts.Iterations = 2
+	_, err = Train(d, opts)
+	assert.Nil(t, err)
+	assert.Equal(t, []int{1, 2}, iterations)
+	_, iteration, err = readCheckpoint(opts.Checkpoint)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, iteration)
+}
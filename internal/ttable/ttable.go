@@ -0,0 +1,100 @@
+// Package ttable implements a fixed-size transposition table keyed by
+// Zobrist hashes, for searches that reach the same position through
+// different move orders.
+package ttable
+
+import (
+	. "github.com/tmazeika/scrabble-go/internal/move"
+	"sync"
+)
+
+// Bound says how Entry.Value relates to the true value of a position.
+type Bound int8
+
+const (
+	Exact Bound = iota
+	Lower
+	Upper
+)
+
+// Entry is what a search remembers about a position. Data can carry any
+// search-specific payload.
+type Entry struct {
+	Key   uint64
+	Depth int
+	Value int
+	Bound Bound
+	Move  Move
+	Data  interface{}
+}
+
+// stripes is the number of locks that guard the table's slots.
+const stripes = 64
+
+// Table maps position hashes to entries. When two positions land on the same
+// slot, the entry searched to the greater depth is kept. It is safe for
+// concurrent use.
+type Table struct {
+	locks   [stripes]sync.Mutex
+	entries []Entry
+	used    []bool
+	mask    uint64
+}
+
+// New returns a table with room for at least size entries.
+func New(size int) *Table {
+	n := 1
+	for n < size {
+		n <<= 1
+	}
+	return &Table{
+		entries: make([]Entry, n),
+		used:    make([]bool, n),
+		mask:    uint64(n - 1),
+	}
+}
+
+// Get returns the entry stored for key, if any.
+func (t *Table) Get(key uint64) (Entry, bool) {
+	i := key & t.mask
+	mu := &t.locks[i%stripes]
+	mu.Lock()
+	defer mu.Unlock()
+	if !t.used[i] || t.entries[i].Key != key {
+		return Entry{}, false
+	}
+	return t.entries[i], true
+}
+
+// Put stores e unless its slot holds a deeper entry for another position.
+func (t *Table) Put(e Entry) {
+	i := e.Key & t.mask
+	mu := &t.locks[i%stripes]
+	mu.Lock()
+	defer mu.Unlock()
+	old := t.entries[i]
+	if t.used[i] && old.Key != e.Key && old.Depth > e.Depth {
+		return
+	}
+	t.entries[i] = e
+	t.used[i] = true
+}
+
+// Clear removes every entry.
+func (t *Table) Clear() {
+	for i := range t.locks {
+		t.locks[i].Lock()
+	}
+	for i := range t.entries {
+		t.entries[i] = Entry{}
+		t.used[i] = false
+	}
+	for i := range t.locks {
+		t.locks[i].Unlock()
+	}
+}
+
+// Len returns the number of slots in the table.
+func (t *Table) Len() int {
+	return len(t.entries)
+}
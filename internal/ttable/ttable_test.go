@@ -0,0 +1,42 @@
+package ttable
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/tmazeika/scrabble-go/internal/move"
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+	tt := New(3)
+	assert.Equal(t, 4, tt.Len())
+	_, ok := tt.Get(1)
+	assert.False(t, ok)
+
+	m := move.Move{Row: 7, Col: 7, Word: "QI"}
+	tt.Put(Entry{Key: 1, Depth: 2, Value: 22, Bound: Lower, Move: m})
+	e, ok := tt.Get(1)
+	assert.True(t, ok)
+	assert.Equal(t, 22, e.Value)
+	assert.Equal(t, Lower, e.Bound)
+	assert.Equal(t, m, e.Move)
+
+	// Key 5 shares a slot with key 1 and loses to its deeper entry.
+	tt.Put(Entry{Key: 5, Depth: 1, Value: 5})
+	_, ok = tt.Get(5)
+	assert.False(t, ok)
+	tt.Put(Entry{Key: 5, Depth: 3, Value: 5})
+	e, ok = tt.Get(5)
+	assert.True(t, ok)
+	assert.Equal(t, 5, e.Value)
+	_, ok = tt.Get(1)
+	assert.False(t, ok)
+
+	// The same position is always replaced.
+	tt.Put(Entry{Key: 5, Depth: 0, Value: 6})
+	e, _ = tt.Get(5)
+	assert.Equal(t, 6, e.Value)
+
+	tt.Clear()
+	_, ok = tt.Get(5)
+	assert.False(t, ok)
+}
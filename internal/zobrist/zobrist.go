@@ -0,0 +1,50 @@
+// Package zobrist provides the random keys that positions are hashed with.
+// A position's hash is the XOR of the keys of everything in it, so it can be
+// updated incrementally as letters are put down and picked up.
+package zobrist
+
+import (
+	. "github.com/tmazeika/scrabble-go/internal/dict"
+)
+
+const (
+	kindSquare = iota + 1
+	kindRack
+	kindSeat
+)
+
+// Square returns the key for letter l on the square with index i of an
+// untransposed board.
+func Square(i int, l Letter) uint64 {
+	return key(kindSquare, uint64(i), uint64(l))
+}
+
+// Rack returns the key for the nth copy (counting from 0) of letter l on a
+// rack.
+func Rack(l Letter, n int) uint64 {
+	return key(kindRack, uint64(l), uint64(n))
+}
+
+// Seat returns the key for the player at index seat being on the move.
+func Seat(seat int) uint64 {
+	return key(kindSeat, uint64(seat), 0)
+}
+
+// Mix combines the hash of a rack with the seat of the player holding it, so
+// that two players holding the same racks in swapped seats hash differently.
+func Mix(seat int, rackHash uint64) uint64 {
+	return splitmix64(rackHash ^ Seat(seat))
+}
+
+func key(kind, a, b uint64) uint64 {
+	return splitmix64(kind<<56 ^ a<<24 ^ b)
+}
+
+// splitmix64 is the finalizer of the SplitMix64 generator, which turns
+// consecutive inputs into well-distributed outputs.
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ x>>30) * 0xbf58476d1ce4e5b9
+	x = (x ^ x>>27) * 0x94d049bb133111eb
+	return x ^ x>>31
+}